@@ -4,9 +4,20 @@
 // @host      localhost:5000
 // @BasePath  /api/v1
 
+// @securityDefinitions.apikey BearerAuth
+// @in                         header
+// @name                       Authorization
+// @description                Access token from /auth/login, sent as "Bearer <token>"
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in                         header
+// @name                       Authorization
+// @description                API key for service clients, sent as "ApiKey <key>"
+
 package main
 
 import (
+	"context"
 	"log"
 	"musiclib/config"
 	_ "musiclib/docs" // Import swagger docs
@@ -14,10 +25,13 @@ import (
 	"musiclib/pkg/db/migrations"
 	"musiclib/pkg/db/postgres"
 	"musiclib/pkg/logger"
+	"musiclib/pkg/tracing"
 	"os"
-	"path/filepath"
+	"time"
 )
 
+const tracingShutdownTimeout = 5 * time.Second
+
 func main() {
 	log.Println("Starting api server")
 
@@ -37,14 +51,18 @@ func main() {
 	appLogger.InitLogger()
 	appLogger.Infof("AppVersion: %s", "LogLevel: %s, Mode: %s", cfg.Server.AppVersion, cfg.Logger.Level, cfg.Server.Mode)
 
+	shutdownTracing, err := tracing.Init(cfg.Tracing, cfg.Server.AppVersion)
+	if err != nil {
+		appLogger.Fatalf("Tracing init: %s", err)
+	}
+
 	db, err := postgres.NewPsqlDB(cfg)
 	if err != nil {
 		appLogger.Fatalf("Postgresql init: %s", err)
 	}
 	appLogger.Infof("Postgres connected, Status: %#v", db.Stats())
 
-	migrationsPath := filepath.Join("migrations")
-	if err := migrations.RunMigrations(db.DB, migrationsPath); err != nil {
+	if err := migrations.RunMigrations(db.DB); err != nil {
 		appLogger.Fatalf("Could not run migrations: %v", err)
 	}
 	appLogger.Info("Migrations completed successfully")
@@ -54,5 +72,11 @@ func main() {
 		appLogger.Fatalf("Error running server: %v", err)
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		appLogger.Errorf("Failed to flush traces: %v", err)
+	}
+
 	defer db.Close()
 }
@@ -12,10 +12,17 @@ import (
 )
 
 type Config struct {
-	Database DatabaseConfig `mapstructure:"database"`
-	Logger   Logger         `mapstructure:"logger"`
-	Server   ServerConfig   `mapstructure:"server"`
-	MusicApi MusicApiConfig `mapstructure:"music_api"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	Logger     Logger           `mapstructure:"logger"`
+	Server     ServerConfig     `mapstructure:"server"`
+	MusicApi   MusicApiConfig   `mapstructure:"music_api"`
+	Trash      TrashConfig      `mapstructure:"trash"`
+	Imports    ImportsConfig    `mapstructure:"imports"`
+	Auth       AuthConfig       `mapstructure:"auth"`
+	RateLimit  RateLimitConfig  `mapstructure:"rate_limit"`
+	Tracing    TracingConfig    `mapstructure:"tracing"`
+	Health     HealthConfig     `mapstructure:"health"`
+	Enrichment EnrichmentConfig `mapstructure:"enrichment"`
 }
 
 type DatabaseConfig struct {
@@ -32,6 +39,8 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
 	Port         string        `mapstructure:"port"`
+	// RequestTimeout is the deadline of a single request, keep it below the write timeout
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
 }
 
 type Logger struct {
@@ -43,8 +52,100 @@ type Logger struct {
 	EnableDebug       bool   `mapstructure:"enable_debug"`
 }
 
+// MusicApiConfig sets up the music API client. Each attempt gets Timeout, timeouts and 5xx answers are retried
+// up to MaxRetries times with a jittered exponential backoff while a whole attempt fits before the caller's
+// deadline, so keep (MaxRetries+1)*Timeout plus the delays below server.request_timeout. After BreakerThreshold
+// failed calls in a row the API is not called for BreakerCooldown. A non-empty APIKey is sent in AuthHeader,
+// prefixed by AuthScheme if set
 type MusicApiConfig struct {
-	URL string `mapstructure:"url"`
+	URL              string        `mapstructure:"url"`
+	Timeout          time.Duration `mapstructure:"timeout"`
+	MaxRetries       int           `mapstructure:"max_retries"`
+	RetryBaseDelay   time.Duration `mapstructure:"retry_base_delay"`
+	RetryMaxDelay    time.Duration `mapstructure:"retry_max_delay"`
+	BreakerThreshold int           `mapstructure:"breaker_threshold"`
+	BreakerCooldown  time.Duration `mapstructure:"breaker_cooldown"`
+	AuthHeader       string        `mapstructure:"auth_header"`
+	AuthScheme       string        `mapstructure:"auth_scheme"`
+	APIKey           string        `mapstructure:"api_key"`
+}
+
+// TrashConfig controls how long deleted songs are kept before they are purged
+type TrashConfig struct {
+	Retention     time.Duration `mapstructure:"retention"`
+	PurgeInterval time.Duration `mapstructure:"purge_interval"`
+}
+
+// ImportsConfig limits bulk imports and sets how often the worker looks for new jobs
+type ImportsConfig struct {
+	PollInterval  time.Duration `mapstructure:"poll_interval"`
+	MaxRows       int           `mapstructure:"max_rows"`
+	MaxUploadSize int64         `mapstructure:"max_upload_size"`
+}
+
+// AuthConfig sets up token signing, the secret is expected in the MUSIC_AUTH_JWT_SECRET variable
+type AuthConfig struct {
+	JWTSecret       string        `mapstructure:"jwt_secret"`
+	Issuer          string        `mapstructure:"issuer"`
+	AccessTokenTTL  time.Duration `mapstructure:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `mapstructure:"refresh_token_ttl"`
+	// AdminUsername and AdminPassword create the initial admin on startup while there is none
+	AdminUsername string `mapstructure:"admin_username"`
+	AdminPassword string `mapstructure:"admin_password"`
+	// AllowRegistration opens /auth/register to anyone, otherwise only admins create accounts
+	AllowRegistration bool `mapstructure:"allow_registration"`
+}
+
+// RateLimitConfig sets the token buckets every client gets, a client is a user, an API key or
+// an IP address. Routes listed by name get a bucket of their own instead of the default one
+type RateLimitConfig struct {
+	Enabled bool                 `mapstructure:"enabled"`
+	Default RateLimit            `mapstructure:"default"`
+	Routes  map[string]RateLimit `mapstructure:"routes"`
+	// AuthFailures limits the failed authentications of an IP address, counted before credentials are checked
+	AuthFailures RateLimit `mapstructure:"auth_failures"`
+	// IdleTimeout drops the buckets of clients that have been quiet for this long
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
+	// TrustForwardedFor takes the client IP from X-Forwarded-For, only enable it behind a proxy
+	TrustForwardedFor bool `mapstructure:"trust_forwarded_for"`
+}
+
+// RateLimit allows Requests per Period on average and bursts of up to Burst requests
+type RateLimit struct {
+	Requests int           `mapstructure:"requests"`
+	Period   time.Duration `mapstructure:"period"`
+	Burst    int           `mapstructure:"burst"`
+}
+
+// TracingConfig picks where OpenTelemetry spans go: "otlp" sends them to Endpoint over OTLP/HTTP,
+// "stdout" prints them and "file" appends them to FilePath
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	ServiceName string  `mapstructure:"service_name"`
+	Exporter    string  `mapstructure:"exporter"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	FilePath    string  `mapstructure:"file_path"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+// HealthConfig bounds the readiness checks. On shutdown the service reports not ready for
+// ShutdownDelay before it stops accepting connections, so load balancers stop routing to it first
+type HealthConfig struct {
+	CheckTimeout  time.Duration `mapstructure:"check_timeout"`
+	ShutdownDelay time.Duration `mapstructure:"shutdown_delay"`
+}
+
+// EnrichmentConfig sets up the workers filling in the details of songs accepted before the music API answered.
+// A failed attempt is retried after RetryDelay, doubled each time up to MaxRetryDelay, until MaxAttempts.
+// A job a worker claimed becomes due again after LeaseTimeout if the worker does not report back
+type EnrichmentConfig struct {
+	Workers       int           `mapstructure:"workers"`
+	PollInterval  time.Duration `mapstructure:"poll_interval"`
+	MaxAttempts   int           `mapstructure:"max_attempts"`
+	RetryDelay    time.Duration `mapstructure:"retry_delay"`
+	MaxRetryDelay time.Duration `mapstructure:"max_retry_delay"`
+	LeaseTimeout  time.Duration `mapstructure:"lease_timeout"`
 }
 
 func LoadConfig(filename string) (*viper.Viper, error) {
@@ -74,6 +175,9 @@ func LoadConfig(filename string) (*viper.Viper, error) {
 	if url := os.Getenv("MUSIC_API_URL"); url != "" {
 		v.Set("music_api.url", url)
 	}
+	if key := os.Getenv("MUSIC_API_KEY"); key != "" {
+		v.Set("music_api.api_key", key)
+	}
 
 	return v, nil
 }
@@ -0,0 +1,15 @@
+package album
+
+import (
+	"net/http"
+)
+
+// Album HTTP Handlers interface
+type Handlers interface {
+	GetList(w http.ResponseWriter, r *http.Request)
+	GetByID(w http.ResponseWriter, r *http.Request)
+	GetTracks(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,320 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"musiclib/config"
+	"musiclib/internal/album"
+	"musiclib/internal/artist"
+	"musiclib/internal/models"
+	"musiclib/pkg/logger"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const defaultLimit = "10"
+const defaultOffset = "0"
+
+// Album handlers
+type albumHandlers struct {
+	cfg        *config.Config
+	albumRepo  album.Repository
+	artistRepo artist.Repository
+	logger     logger.Logger
+}
+
+// NewAlbumHandlers Album handlers constructor
+func NewAlbumHandlers(cfg *config.Config, logger logger.Logger, repo album.Repository, artistRepo artist.Repository) *albumHandlers {
+	return &albumHandlers{cfg: cfg, logger: logger, albumRepo: repo, artistRepo: artistRepo}
+}
+
+// @Summary     List albums
+// @Description Get paginated list of albums ordered by release date
+// @Tags        albums
+// @Accept      json
+// @Produce     json
+// @Param       artist_id query int false "Only albums of this artist"
+// @Param       limit query int false "Number of items to return (default: 10)"
+// @Param       offset query int false "Number of items to skip (default: 0)"
+// @Success     200 {array} models.Album
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     500 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /albums/list [get]
+func (h *albumHandlers) GetList(w http.ResponseWriter, r *http.Request) {
+	artistID := r.URL.Query().Get("artist_id")
+	limit := r.URL.Query().Get("limit")
+	offset := r.URL.Query().Get("offset")
+
+	if artistID == "" {
+		artistID = "0"
+	}
+	if limit == "" {
+		limit = defaultLimit
+	}
+	if offset == "" {
+		offset = defaultOffset
+	}
+
+	artistIDInt, err := strconv.Atoi(artistID)
+	if err != nil {
+		http.Error(w, "Invalid artist ID", http.StatusBadRequest)
+		return
+	}
+	limitInt, err := strconv.Atoi(limit)
+	if err != nil {
+		http.Error(w, "Invalid limit value", http.StatusBadRequest)
+		return
+	}
+	offsetInt, err := strconv.Atoi(offset)
+	if err != nil {
+		http.Error(w, "Invalid offset value", http.StatusBadRequest)
+		return
+	}
+
+	albums, err := h.albumRepo.GetList(r.Context(), artistIDInt, limitInt, offsetInt)
+	if err != nil {
+		h.logger.Error("Error getting albums from repository", err)
+		http.Error(w, "Error getting albums", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, albums)
+}
+
+// @Summary     Get album
+// @Description Get an album by ID
+// @Tags        albums
+// @Accept      json
+// @Produce     json
+// @Param       id query int true "Album ID"
+// @Success     200 {object} models.Album
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /albums/ [get]
+func (h *albumHandlers) GetByID(w http.ResponseWriter, r *http.Request) {
+	albumID, ok := parseAlbumID(w, r)
+	if !ok {
+		return
+	}
+
+	a, err := h.albumRepo.GetByID(r.Context(), albumID)
+	if err != nil {
+		h.handleError(w, err, "Error getting album")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, a)
+}
+
+// @Summary     Get album tracklist
+// @Description Get the album with its songs ordered by disc and track number
+// @Tags        albums
+// @Accept      json
+// @Produce     json
+// @Param       id query int true "Album ID"
+// @Success     200 {object} models.AlbumTracklist
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /albums/tracks [get]
+func (h *albumHandlers) GetTracks(w http.ResponseWriter, r *http.Request) {
+	albumID, ok := parseAlbumID(w, r)
+	if !ok {
+		return
+	}
+
+	a, err := h.albumRepo.GetByID(r.Context(), albumID)
+	if err != nil {
+		h.handleError(w, err, "Error getting album")
+		return
+	}
+
+	tracks, err := h.albumRepo.GetTracks(r.Context(), albumID)
+	if err != nil {
+		h.handleError(w, err, "Error getting album tracks")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, models.AlbumTracklist{Album: *a, Tracks: tracks})
+}
+
+// @Summary     Create album
+// @Description Create a new album, the artist is referenced by ID or resolved by name
+// @Tags        albums
+// @Accept      json
+// @Produce     json
+// @Param       request body models.AlbumRequest true "Album request"
+// @Success     201 {object} models.Album
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     500 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /albums/ [post]
+func (h *albumHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	a, ok := h.decodeAlbum(w, r)
+	if !ok {
+		return
+	}
+
+	created, err := h.albumRepo.Create(r.Context(), a)
+	if err != nil {
+		h.handleError(w, err, "Error creating album")
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, created)
+}
+
+// @Summary     Update album
+// @Description Update album details by ID
+// @Tags        albums
+// @Accept      json
+// @Produce     json
+// @Param       id query int true "Album ID"
+// @Param       request body models.AlbumRequest true "Album request"
+// @Success     200 {object} models.Album
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /albums/ [put]
+func (h *albumHandlers) Update(w http.ResponseWriter, r *http.Request) {
+	albumID, ok := parseAlbumID(w, r)
+	if !ok {
+		return
+	}
+
+	a, ok := h.decodeAlbum(w, r)
+	if !ok {
+		return
+	}
+	a.ID = albumID
+
+	updated, err := h.albumRepo.Update(r.Context(), a)
+	if err != nil {
+		h.handleError(w, err, "Error updating album")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, updated)
+}
+
+// @Summary     Delete album
+// @Description Delete an album by ID, its songs stay in the library
+// @Tags        albums
+// @Accept      json
+// @Produce     plain
+// @Param       id query int true "Album ID"
+// @Success     200 {string} string "Album deleted successfully"
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /albums/ [delete]
+func (h *albumHandlers) Delete(w http.ResponseWriter, r *http.Request) {
+	albumID, ok := parseAlbumID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.albumRepo.Delete(r.Context(), albumID); err != nil {
+		h.handleError(w, err, "Error deleting album")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Album deleted successfully"))
+}
+
+// decodeAlbum reads and validates an album request, resolving the artist by name when no ID is given
+func (h *albumHandlers) decodeAlbum(w http.ResponseWriter, r *http.Request) (*models.Album, bool) {
+	var req models.AlbumRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+
+	if strings.TrimSpace(req.Title) == "" {
+		http.Error(w, "Title is required", http.StatusBadRequest)
+		return nil, false
+	}
+
+	if req.Type == "" {
+		req.Type = models.AlbumTypeLP
+	}
+	if !models.IsValidAlbumType(req.Type) {
+		http.Error(w, "Type must be one of: LP, EP, single, compilation", http.StatusBadRequest)
+		return nil, false
+	}
+
+	releaseDate, err := models.ParseReleaseDate(req.ReleaseDate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	if req.ArtistID == 0 {
+		if strings.TrimSpace(req.Artist) == "" {
+			http.Error(w, "artistId or artist is required", http.StatusBadRequest)
+			return nil, false
+		}
+
+		albumArtist, err := h.artistRepo.GetOrCreate(r.Context(), req.Artist)
+		if err != nil {
+			h.logger.Error("Failed to resolve artist", err)
+			http.Error(w, "Failed to resolve artist", http.StatusInternalServerError)
+			return nil, false
+		}
+		req.ArtistID = albumArtist.ID
+	}
+
+	return &models.Album{
+		Title:       strings.TrimSpace(req.Title),
+		ArtistID:    req.ArtistID,
+		ReleaseDate: releaseDate,
+		Type:        req.Type,
+	}, true
+}
+
+// handleError maps repository errors to HTTP responses
+func (h *albumHandlers) handleError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, album.ErrNotFound):
+		http.Error(w, "Album not found", http.StatusNotFound)
+	case errors.Is(err, album.ErrArtistNotFound):
+		http.Error(w, "Artist not found", http.StatusBadRequest)
+	default:
+		h.logger.Error(message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+func (h *albumHandlers) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
+
+func parseAlbumID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "Album ID is required", http.StatusBadRequest)
+		return 0, false
+	}
+
+	albumID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, "Invalid album ID", http.StatusBadRequest)
+		return 0, false
+	}
+
+	return albumID, true
+}
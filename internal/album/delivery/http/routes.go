@@ -0,0 +1,17 @@
+package http
+
+import (
+	"musiclib/internal/album"
+
+	"github.com/gorilla/mux"
+)
+
+// Map albums routes
+func MapAlbumRoutes(albumsGroup *mux.Router, h album.Handlers) {
+	albumsGroup.HandleFunc("/list", h.GetList).Methods("GET")
+	albumsGroup.HandleFunc("/tracks", h.GetTracks).Methods("GET")
+	albumsGroup.HandleFunc("/", h.GetByID).Methods("GET")
+	albumsGroup.HandleFunc("/", h.Create).Methods("POST")
+	albumsGroup.HandleFunc("/", h.Update).Methods("PUT")
+	albumsGroup.HandleFunc("/", h.Delete).Methods("DELETE")
+}
@@ -0,0 +1,8 @@
+package album
+
+import "errors"
+
+var (
+	ErrNotFound       = errors.New("album not found")
+	ErrArtistNotFound = errors.New("artist not found")
+)
@@ -0,0 +1,16 @@
+package album
+
+import (
+	"context"
+	"musiclib/internal/models"
+)
+
+// Repository interface
+type Repository interface {
+	GetList(ctx context.Context, artistID int, limit int, offset int) ([]models.Album, error)
+	GetByID(ctx context.Context, id int) (*models.Album, error)
+	GetTracks(ctx context.Context, id int) ([]models.AlbumTrack, error)
+	Create(ctx context.Context, album *models.Album) (*models.Album, error)
+	Update(ctx context.Context, album *models.Album) (*models.Album, error)
+	Delete(ctx context.Context, id int) error
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"musiclib/internal/album"
+	"musiclib/internal/models"
+	"musiclib/pkg/db/postgres"
+	"musiclib/pkg/logger"
+	"musiclib/pkg/metrics"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type albumRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+func NewAlbumRepository(db *sqlx.DB, logger logger.Logger) *albumRepository {
+	return &albumRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *albumRepository) GetList(ctx context.Context, artistID int, limit int, offset int) ([]models.Album, error) {
+	defer metrics.ObserveQuery("album", "GetList")()
+	r.logger.Debug("Starting GetList in album repository",
+		"artistID", artistID,
+		"limit", limit,
+		"offset", offset,
+	)
+
+	albums := make([]models.Album, 0)
+	if err := r.db.SelectContext(ctx, &albums, getAlbums, artistID, limit, offset); err != nil {
+		r.logger.Debug("Failed to get albums", "error", err)
+		return nil, fmt.Errorf("failed to get albums list: %w", err)
+	}
+
+	r.logger.Debug("Successfully retrieved albums", "count", len(albums))
+	return albums, nil
+}
+
+func (r *albumRepository) GetByID(ctx context.Context, id int) (*models.Album, error) {
+	defer metrics.ObserveQuery("album", "GetByID")()
+	r.logger.Debug("Starting GetByID in album repository", "id", id)
+
+	var a models.Album
+	if err := r.db.GetContext(ctx, &a, getAlbumByID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, album.ErrNotFound
+		}
+		r.logger.Debug("Failed to get album", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to get album: %w", err)
+	}
+
+	return &a, nil
+}
+
+// GetTracks returns the album tracklist ordered by disc and track number
+func (r *albumRepository) GetTracks(ctx context.Context, id int) ([]models.AlbumTrack, error) {
+	defer metrics.ObserveQuery("album", "GetTracks")()
+	r.logger.Debug("Starting GetTracks in album repository", "id", id)
+
+	tracks := make([]models.AlbumTrack, 0)
+	if err := r.db.SelectContext(ctx, &tracks, getAlbumTracks, id); err != nil {
+		r.logger.Debug("Failed to get album tracks", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to get album tracks: %w", err)
+	}
+
+	r.logger.Debug("Successfully retrieved album tracks", "id", id, "count", len(tracks))
+	return tracks, nil
+}
+
+func (r *albumRepository) Create(ctx context.Context, a *models.Album) (*models.Album, error) {
+	defer metrics.ObserveQuery("album", "Create")()
+	r.logger.Debug("Starting Create in album repository", "title", a.Title, "artistID", a.ArtistID)
+
+	var id int
+	err := r.db.QueryRowContext(ctx, createAlbum,
+		a.Title,
+		a.ArtistID,
+		a.ReleaseDate,
+		string(a.ReleaseDate.Precision),
+		a.Type,
+	).Scan(&id)
+	if err != nil {
+		if postgres.IsForeignKeyViolation(err) {
+			return nil, album.ErrArtistNotFound
+		}
+		r.logger.Debug("Failed to create album", "error", err)
+		return nil, fmt.Errorf("failed to create album: %w", err)
+	}
+
+	r.logger.Debug("Successfully created album", "id", id)
+	return r.GetByID(ctx, id)
+}
+
+func (r *albumRepository) Update(ctx context.Context, a *models.Album) (*models.Album, error) {
+	defer metrics.ObserveQuery("album", "Update")()
+	r.logger.Debug("Starting Update in album repository", "id", a.ID, "title", a.Title)
+
+	var id int
+	err := r.db.QueryRowContext(ctx, updateAlbum,
+		a.Title,
+		a.ArtistID,
+		a.ReleaseDate,
+		string(a.ReleaseDate.Precision),
+		a.Type,
+		a.ID,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, album.ErrNotFound
+		}
+		if postgres.IsForeignKeyViolation(err) {
+			return nil, album.ErrArtistNotFound
+		}
+		r.logger.Debug("Failed to update album", "error", err, "id", a.ID)
+		return nil, fmt.Errorf("failed to update album: %w", err)
+	}
+
+	r.logger.Debug("Successfully updated album", "id", id)
+	return r.GetByID(ctx, id)
+}
+
+func (r *albumRepository) Delete(ctx context.Context, id int) error {
+	defer metrics.ObserveQuery("album", "Delete")()
+	r.logger.Debug("Starting Delete in album repository", "id", id)
+
+	result, err := r.db.ExecContext(ctx, deleteAlbum, id)
+	if err != nil {
+		r.logger.Debug("Failed to delete album", "error", err, "id", id)
+		return fmt.Errorf("failed to delete album: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return album.ErrNotFound
+	}
+
+	r.logger.Debug("Successfully deleted album", "id", id)
+	return nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+const albumColumns = `al.id, al.title, al.artist_id, a.name AS artist_name,
+    format_release_date(al.release_date, al.release_date_precision) AS release_date, al.type,
+    (SELECT COUNT(*) FROM songs s WHERE s.album_id = al.id AND s.deleted_at IS NULL) AS tracks_count,
+    al.created_at, al.updated_at`
+
+const getAlbums = `
+    SELECT ` + albumColumns + `
+    FROM albums al
+    JOIN artists a ON a.id = al.artist_id
+    WHERE ($1 = 0 OR al.artist_id = $1)
+    ORDER BY al.release_date NULLS LAST, al.title, al.id
+    LIMIT $2 OFFSET $3`
+
+const getAlbumByID = `
+    SELECT ` + albumColumns + `
+    FROM albums al
+    JOIN artists a ON a.id = al.artist_id
+    WHERE al.id = $1`
+
+const getAlbumTracks = `
+    SELECT s.disc_number, s.track_number, s.id AS song_id, COALESCE(s.song, '') AS song,
+           COALESCE(a.name, '') AS group_name, COALESCE(s.link, '') AS link
+    FROM songs s
+    LEFT JOIN artists a ON a.id = s.artist_id
+    WHERE s.album_id = $1 AND s.deleted_at IS NULL
+    ORDER BY s.disc_number, s.track_number`
+
+const createAlbum = `
+    INSERT INTO albums (title, artist_id, release_date, release_date_precision, type)
+    VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+    RETURNING id`
+
+const updateAlbum = `
+    UPDATE albums
+    SET title = $1,
+        artist_id = $2,
+        release_date = $3,
+        release_date_precision = NULLIF($4, ''),
+        type = $5,
+        updated_at = NOW()
+    WHERE id = $6
+    RETURNING id`
+
+const deleteAlbum = `DELETE FROM albums WHERE id = $1`
@@ -0,0 +1,14 @@
+package apikey
+
+import (
+	"net/http"
+)
+
+// API key HTTP Handlers interface
+type Handlers interface {
+	GetList(w http.ResponseWriter, r *http.Request)
+	GetByID(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Revoke(w http.ResponseWriter, r *http.Request)
+	Rotate(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,287 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"musiclib/config"
+	"musiclib/internal/apikey"
+	"musiclib/internal/auth"
+	"musiclib/internal/models"
+	"musiclib/pkg/logger"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+)
+
+const defaultLimit = "10"
+const defaultOffset = "0"
+
+const apiKeysPath = "/api/v1/api-keys"
+
+// maxNameLength matches the size of the api_keys name column
+const maxNameLength = 100
+
+// API key handlers
+type apiKeyHandlers struct {
+	cfg        *config.Config
+	apiKeyRepo apikey.Repository
+	logger     logger.Logger
+}
+
+// NewAPIKeyHandlers API key handlers constructor
+func NewAPIKeyHandlers(cfg *config.Config, logger logger.Logger, repo apikey.Repository) *apiKeyHandlers {
+	return &apiKeyHandlers{cfg: cfg, logger: logger, apiKeyRepo: repo}
+}
+
+// @Summary     List API keys
+// @Description Get paginated list of API keys with their usage, live keys first. Admins only.
+// @Tags        api-keys
+// @Accept      json
+// @Produce     json
+// @Param       limit query int false "Number of items to return (default: 10)"
+// @Param       offset query int false "Number of items to skip (default: 0)"
+// @Success     200 {array} models.APIKey
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     403 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /api-keys [get]
+func (h *apiKeyHandlers) GetList(w http.ResponseWriter, r *http.Request) {
+	limit := r.URL.Query().Get("limit")
+	offset := r.URL.Query().Get("offset")
+
+	if limit == "" {
+		limit = defaultLimit
+	}
+	if offset == "" {
+		offset = defaultOffset
+	}
+
+	limitInt, err := strconv.Atoi(limit)
+	if err != nil {
+		http.Error(w, "Invalid limit value", http.StatusBadRequest)
+		return
+	}
+	offsetInt, err := strconv.Atoi(offset)
+	if err != nil {
+		http.Error(w, "Invalid offset value", http.StatusBadRequest)
+		return
+	}
+
+	keys, err := h.apiKeyRepo.GetList(r.Context(), limitInt, offsetInt)
+	if err != nil {
+		h.logger.Error("Error getting API keys from repository", err)
+		http.Error(w, "Error getting API keys", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, keys)
+}
+
+// @Summary     Get API key
+// @Description Get an API key with its usage by ID. Admins only.
+// @Tags        api-keys
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "API key ID"
+// @Success     200 {object} models.APIKey
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     403 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /api-keys/{id} [get]
+func (h *apiKeyHandlers) GetByID(w http.ResponseWriter, r *http.Request) {
+	keyID, ok := parseAPIKeyID(w, r)
+	if !ok {
+		return
+	}
+
+	key, err := h.apiKeyRepo.GetByID(r.Context(), keyID)
+	if err != nil {
+		h.handleError(w, err, "Error getting API key")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, key)
+}
+
+// @Summary     Create API key
+// @Description Create an API key for a service client. The key is only returned in this response,
+// @Description send it as "Authorization: ApiKey <key>". Scopes: songs:read and songs:write reach the songs routes only,
+// @Description admin reaches everything. Admins only.
+// @Tags        api-keys
+// @Accept      json
+// @Produce     json
+// @Param       request body models.CreateAPIKeyRequest true "API key request"
+// @Success     201 {object} models.APIKeySecret
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     403 {object} models.ErrorResponse
+// @Failure     409 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /api-keys [post]
+func (h *apiKeyHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+	if utf8.RuneCountInString(req.Name) > maxNameLength {
+		http.Error(w, "Name is too long", http.StatusBadRequest)
+		return
+	}
+	if len(req.Scopes) == 0 {
+		http.Error(w, "At least one scope is required", http.StatusBadRequest)
+		return
+	}
+	for _, scope := range req.Scopes {
+		if !models.IsValidScope(scope) {
+			http.Error(w, fmt.Sprintf("Invalid scope %q, use songs:read, songs:write or admin", scope), http.StatusBadRequest)
+			return
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		http.Error(w, "expiresAt must be in the future", http.StatusBadRequest)
+		return
+	}
+
+	key, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		h.handleError(w, err, "Error creating API key")
+		return
+	}
+
+	newKey := &models.APIKey{
+		Name:      req.Name,
+		Prefix:    prefix,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if principal, ok := auth.PrincipalFrom(r.Context()); ok && principal.UserID != 0 {
+		newKey.CreatedBy = &principal.UserID
+	}
+
+	created, err := h.apiKeyRepo.Create(r.Context(), newKey, hash)
+	if err != nil {
+		h.handleError(w, err, "Error creating API key")
+		return
+	}
+
+	h.logger.Info("API key created", "id", created.ID, "name", created.Name, "scopes", created.Scopes)
+	w.Header().Set("Location", fmt.Sprintf("%s/%d", apiKeysPath, created.ID))
+	h.writeSecret(w, http.StatusCreated, created, key)
+}
+
+// @Summary     Revoke API key
+// @Description Disable an API key for good, its record and usage stay listed. Admins only.
+// @Tags        api-keys
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "API key ID"
+// @Success     200 {object} models.APIKey
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     403 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /api-keys/{id}/revoke [post]
+func (h *apiKeyHandlers) Revoke(w http.ResponseWriter, r *http.Request) {
+	keyID, ok := parseAPIKeyID(w, r)
+	if !ok {
+		return
+	}
+
+	revoked, err := h.apiKeyRepo.Revoke(r.Context(), keyID)
+	if err != nil {
+		h.handleError(w, err, "Error revoking API key")
+		return
+	}
+
+	h.logger.Info("API key revoked", "id", revoked.ID, "name", revoked.Name)
+	h.writeJSON(w, http.StatusOK, revoked)
+}
+
+// @Summary     Rotate API key
+// @Description Replace the secret of an API key, the old key stops working at once.
+// @Description The new key is only returned in this response. Admins only.
+// @Tags        api-keys
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "API key ID"
+// @Success     200 {object} models.APIKeySecret
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     403 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Failure     409 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /api-keys/{id}/rotate [post]
+func (h *apiKeyHandlers) Rotate(w http.ResponseWriter, r *http.Request) {
+	keyID, ok := parseAPIKeyID(w, r)
+	if !ok {
+		return
+	}
+
+	key, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		h.handleError(w, err, "Error rotating API key")
+		return
+	}
+
+	rotated, err := h.apiKeyRepo.Rotate(r.Context(), keyID, prefix, hash)
+	if err != nil {
+		h.handleError(w, err, "Error rotating API key")
+		return
+	}
+
+	h.logger.Info("API key rotated", "id", rotated.ID, "name", rotated.Name)
+	h.writeSecret(w, http.StatusOK, rotated, key)
+}
+
+// handleError maps repository errors to HTTP responses
+func (h *apiKeyHandlers) handleError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, apikey.ErrNotFound):
+		http.Error(w, "API key not found", http.StatusNotFound)
+	case errors.Is(err, apikey.ErrAlreadyExists), errors.Is(err, apikey.ErrRevoked):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		h.logger.Error(message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+// writeSecret sends the key with its plain secret, the response must not be cached
+func (h *apiKeyHandlers) writeSecret(w http.ResponseWriter, status int, key *models.APIKey, secret string) {
+	w.Header().Set("Cache-Control", "no-store")
+	h.writeJSON(w, status, models.APIKeySecret{APIKey: *key, Key: secret})
+}
+
+func (h *apiKeyHandlers) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
+
+func parseAPIKeyID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	keyID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid API key ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return keyID, true
+}
@@ -0,0 +1,16 @@
+package http
+
+import (
+	"musiclib/internal/apikey"
+
+	"github.com/gorilla/mux"
+)
+
+// Map API keys routes
+func MapAPIKeyRoutes(keysGroup *mux.Router, h apikey.Handlers) {
+	keysGroup.HandleFunc("", h.GetList).Methods("GET")
+	keysGroup.HandleFunc("", h.Create).Methods("POST")
+	keysGroup.HandleFunc("/{id:[0-9]+}", h.GetByID).Methods("GET")
+	keysGroup.HandleFunc("/{id:[0-9]+}/revoke", h.Revoke).Methods("POST")
+	keysGroup.HandleFunc("/{id:[0-9]+}/rotate", h.Rotate).Methods("POST")
+}
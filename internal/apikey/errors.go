@@ -0,0 +1,10 @@
+package apikey
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("API key not found")
+	ErrAlreadyExists = errors.New("API key with this name already exists")
+	ErrRevoked       = errors.New("API key is revoked")
+	ErrInvalidKey    = errors.New("invalid, revoked or expired API key")
+)
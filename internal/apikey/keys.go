@@ -0,0 +1,34 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// keyPrefix marks musicLib keys so they are easy to spot in configs and secret scanners
+const keyPrefix = "mlk_"
+
+const (
+	keyBytes      = 32
+	displayLength = len(keyPrefix) + 8
+)
+
+// Generate returns a new random key, the part of it shown in listings and its hash
+func Generate() (key string, prefix string, hash string, err error) {
+	buf := make([]byte, keyBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	key = keyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return key, key[:displayLength], Hash(key), nil
+}
+
+// Hash returns the form keys are stored and looked up in
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,16 @@
+package apikey
+
+import (
+	"context"
+	"musiclib/internal/models"
+)
+
+// Repository interface
+type Repository interface {
+	Create(ctx context.Context, key *models.APIKey, keyHash string) (*models.APIKey, error)
+	GetList(ctx context.Context, limit int, offset int) ([]models.APIKey, error)
+	GetByID(ctx context.Context, id int) (*models.APIKey, error)
+	Revoke(ctx context.Context, id int) (*models.APIKey, error)
+	Rotate(ctx context.Context, id int, prefix string, keyHash string) (*models.APIKey, error)
+	Authenticate(ctx context.Context, keyHash string) (*models.APIKey, error)
+}
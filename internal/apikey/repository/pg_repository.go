@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"musiclib/internal/apikey"
+	"musiclib/internal/models"
+	"musiclib/pkg/db/postgres"
+	"musiclib/pkg/logger"
+	"musiclib/pkg/metrics"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// apiKeyRow scans the scopes array, which models.APIKey keeps as a plain slice
+type apiKeyRow struct {
+	models.APIKey
+	ScopesArray pq.StringArray `db:"scopes"`
+}
+
+func (row apiKeyRow) toModel() *models.APIKey {
+	key := row.APIKey
+	key.Scopes = []string(row.ScopesArray)
+	return &key
+}
+
+type apiKeyRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+func NewAPIKeyRepository(db *sqlx.DB, logger logger.Logger) *apiKeyRepository {
+	return &apiKeyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey, keyHash string) (*models.APIKey, error) {
+	defer metrics.ObserveQuery("apikey", "Create")()
+	r.logger.Debug("Starting Create in API key repository", "name", key.Name, "scopes", key.Scopes)
+
+	createdBy := 0
+	if key.CreatedBy != nil {
+		createdBy = *key.CreatedBy
+	}
+
+	var row apiKeyRow
+	err := r.db.GetContext(ctx, &row, createAPIKey,
+		key.Name,
+		models.NormalizeAPIKeyName(key.Name),
+		key.Prefix,
+		keyHash,
+		pq.Array(key.Scopes),
+		createdBy,
+		key.ExpiresAt,
+	)
+	if err != nil {
+		if postgres.IsUniqueViolation(err) {
+			return nil, apikey.ErrAlreadyExists
+		}
+		r.logger.Debug("Failed to create API key", "error", err)
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	r.logger.Debug("Successfully created API key", "id", row.ID)
+	return row.toModel(), nil
+}
+
+// GetList returns live keys first, then the revoked ones
+func (r *apiKeyRepository) GetList(ctx context.Context, limit int, offset int) ([]models.APIKey, error) {
+	defer metrics.ObserveQuery("apikey", "GetList")()
+	r.logger.Debug("Starting GetList in API key repository", "limit", limit, "offset", offset)
+
+	var rows []apiKeyRow
+	if err := r.db.SelectContext(ctx, &rows, getAPIKeys, limit, offset); err != nil {
+		r.logger.Debug("Failed to get API keys", "error", err)
+		return nil, fmt.Errorf("failed to get API keys list: %w", err)
+	}
+
+	keys := make([]models.APIKey, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, *row.toModel())
+	}
+
+	r.logger.Debug("Successfully retrieved API keys", "count", len(keys))
+	return keys, nil
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id int) (*models.APIKey, error) {
+	defer metrics.ObserveQuery("apikey", "GetByID")()
+	r.logger.Debug("Starting GetByID in API key repository", "id", id)
+	return r.getKey(ctx, getAPIKeyByID, apikey.ErrNotFound, id)
+}
+
+// Revoke disables the key for good, the record stays for its usage history
+func (r *apiKeyRepository) Revoke(ctx context.Context, id int) (*models.APIKey, error) {
+	defer metrics.ObserveQuery("apikey", "Revoke")()
+	r.logger.Debug("Starting Revoke in API key repository", "id", id)
+	return r.getKey(ctx, revokeAPIKey, apikey.ErrNotFound, id)
+}
+
+// Rotate gives the key a new secret and keeps its name, scopes and usage counters
+func (r *apiKeyRepository) Rotate(ctx context.Context, id int, prefix string, keyHash string) (*models.APIKey, error) {
+	defer metrics.ObserveQuery("apikey", "Rotate")()
+	r.logger.Debug("Starting Rotate in API key repository", "id", id)
+
+	key, err := r.getKey(ctx, rotateAPIKey, apikey.ErrNotFound, id, prefix, keyHash)
+	if errors.Is(err, apikey.ErrNotFound) {
+		// Tell a revoked key from a missing one
+		if _, getErr := r.GetByID(ctx, id); getErr == nil {
+			return nil, apikey.ErrRevoked
+		}
+	}
+	return key, err
+}
+
+// Authenticate returns the live key with the hash and records its use
+func (r *apiKeyRepository) Authenticate(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	defer metrics.ObserveQuery("apikey", "Authenticate")()
+	return r.getKey(ctx, useAPIKey, apikey.ErrInvalidKey, keyHash)
+}
+
+func (r *apiKeyRepository) getKey(ctx context.Context, query string, notFound error, args ...interface{}) (*models.APIKey, error) {
+	var row apiKeyRow
+	if err := r.db.GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFound
+		}
+		r.logger.Debug("Failed to get API key", "error", err)
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return row.toModel(), nil
+}
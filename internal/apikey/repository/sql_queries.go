@@ -0,0 +1,37 @@
+package repository
+
+const apiKeyColumns = `id, name, prefix, scopes, created_by, created_at, expires_at, rotated_at, revoked_at,
+    last_used_at, request_count`
+
+const createAPIKey = `
+    INSERT INTO api_keys (name, normalized_name, prefix, key_hash, scopes, created_by, expires_at)
+    VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), $7)
+    RETURNING ` + apiKeyColumns
+
+const getAPIKeys = `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY revoked_at NULLS FIRST, name, id LIMIT $1 OFFSET $2`
+
+const getAPIKeyByID = `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = $1`
+
+// revokeAPIKey keeps the original revocation time when the key is revoked twice
+const revokeAPIKey = `
+    UPDATE api_keys
+    SET revoked_at = COALESCE(revoked_at, NOW())
+    WHERE id = $1
+    RETURNING ` + apiKeyColumns
+
+// rotateAPIKey replaces the secret of a live key, the old secret stops working at once
+const rotateAPIKey = `
+    UPDATE api_keys
+    SET prefix = $2,
+        key_hash = $3,
+        rotated_at = NOW()
+    WHERE id = $1 AND revoked_at IS NULL
+    RETURNING ` + apiKeyColumns
+
+// useAPIKey checks the key and counts the request in the same statement
+const useAPIKey = `
+    UPDATE api_keys
+    SET last_used_at = NOW(),
+        request_count = request_count + 1
+    WHERE key_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+    RETURNING ` + apiKeyColumns
@@ -0,0 +1,15 @@
+package artist
+
+import (
+	"net/http"
+)
+
+// Artist HTTP Handlers interface
+type Handlers interface {
+	GetList(w http.ResponseWriter, r *http.Request)
+	GetByID(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Merge(w http.ResponseWriter, r *http.Request)
+}
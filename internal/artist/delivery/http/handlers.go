@@ -0,0 +1,294 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"musiclib/config"
+	"musiclib/internal/artist"
+	"musiclib/internal/models"
+	"musiclib/pkg/logger"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const defaultLimit = "10"
+const defaultOffset = "0"
+
+// Artist handlers
+type artistHandlers struct {
+	cfg        *config.Config
+	artistRepo artist.Repository
+	logger     logger.Logger
+}
+
+// NewArtistHandlers Artist handlers constructor
+func NewArtistHandlers(cfg *config.Config, logger logger.Logger, repo artist.Repository) *artistHandlers {
+	return &artistHandlers{cfg: cfg, logger: logger, artistRepo: repo}
+}
+
+// @Summary     List artists
+// @Description Get paginated list of artists ordered by name
+// @Tags        artists
+// @Accept      json
+// @Produce     json
+// @Param       limit query int false "Number of items to return (default: 10)"
+// @Param       offset query int false "Number of items to skip (default: 0)"
+// @Success     200 {array} models.Artist
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     500 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /artists/list [get]
+func (h *artistHandlers) GetList(w http.ResponseWriter, r *http.Request) {
+	limit := r.URL.Query().Get("limit")
+	offset := r.URL.Query().Get("offset")
+
+	if limit == "" {
+		limit = defaultLimit
+	}
+	if offset == "" {
+		offset = defaultOffset
+	}
+
+	limitInt, err := strconv.Atoi(limit)
+	if err != nil {
+		http.Error(w, "Invalid limit value", http.StatusBadRequest)
+		return
+	}
+	offsetInt, err := strconv.Atoi(offset)
+	if err != nil {
+		http.Error(w, "Invalid offset value", http.StatusBadRequest)
+		return
+	}
+
+	artists, err := h.artistRepo.GetList(r.Context(), limitInt, offsetInt)
+	if err != nil {
+		h.logger.Error("Error getting artists from repository", err)
+		http.Error(w, "Error getting artists", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, artists)
+}
+
+// @Summary     Get artist
+// @Description Get an artist by ID
+// @Tags        artists
+// @Accept      json
+// @Produce     json
+// @Param       id query int true "Artist ID"
+// @Success     200 {object} models.Artist
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /artists/ [get]
+func (h *artistHandlers) GetByID(w http.ResponseWriter, r *http.Request) {
+	artistID, ok := parseArtistID(w, r)
+	if !ok {
+		return
+	}
+
+	a, err := h.artistRepo.GetByID(r.Context(), artistID)
+	if err != nil {
+		h.handleError(w, err, "Error getting artist")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, a)
+}
+
+// @Summary     Create artist
+// @Description Create a new artist
+// @Tags        artists
+// @Accept      json
+// @Produce     json
+// @Param       request body models.ArtistRequest true "Artist request"
+// @Success     201 {object} models.Artist
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     409 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /artists/ [post]
+func (h *artistHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.ArtistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.artistRepo.Create(r.Context(), &models.Artist{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		h.handleError(w, err, "Error creating artist")
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, created)
+}
+
+// @Summary     Update artist
+// @Description Rename an artist or change its description
+// @Tags        artists
+// @Accept      json
+// @Produce     json
+// @Param       id query int true "Artist ID"
+// @Param       request body models.ArtistRequest true "Artist request"
+// @Success     200 {object} models.Artist
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Failure     409 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /artists/ [put]
+func (h *artistHandlers) Update(w http.ResponseWriter, r *http.Request) {
+	artistID, ok := parseArtistID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.ArtistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.artistRepo.Update(r.Context(), &models.Artist{
+		ID:          artistID,
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		h.handleError(w, err, "Error updating artist")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, updated)
+}
+
+// @Summary     Delete artist
+// @Description Delete an artist that has no songs or albums
+// @Tags        artists
+// @Accept      json
+// @Produce     plain
+// @Param       id query int true "Artist ID"
+// @Success     200 {string} string "Artist deleted successfully"
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Failure     409 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /artists/ [delete]
+func (h *artistHandlers) Delete(w http.ResponseWriter, r *http.Request) {
+	artistID, ok := parseArtistID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.artistRepo.Delete(r.Context(), artistID); err != nil {
+		h.handleError(w, err, "Error deleting artist")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Artist deleted successfully"))
+}
+
+// @Summary     Merge artists
+// @Description Move all songs and albums of the source artist to the target artist and delete the source.
+// @Description Every moved song gets an update revision with source artist_merge.
+// @Description The merge is refused with 409 when both artists have songs with the same title,
+// @Description the response lists them so one of each pair can be deleted or renamed first.
+// @Tags        artists
+// @Accept      json
+// @Produce     json
+// @Param       request body models.MergeArtistsRequest true "Merge request"
+// @Success     200 {object} models.Artist
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Failure     409 {object} models.MergeConflictResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /artists/merge [post]
+func (h *artistHandlers) Merge(w http.ResponseWriter, r *http.Request) {
+	var req models.MergeArtistsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.SourceID <= 0 || req.TargetID <= 0 {
+		http.Error(w, "sourceId and targetId are required", http.StatusBadRequest)
+		return
+	}
+	if req.SourceID == req.TargetID {
+		http.Error(w, "Cannot merge an artist into itself", http.StatusBadRequest)
+		return
+	}
+
+	merged, err := h.artistRepo.Merge(r.Context(), req.SourceID, req.TargetID)
+	var conflict *artist.TitleConflictError
+	if errors.As(err, &conflict) {
+		h.writeJSON(w, http.StatusConflict, models.MergeConflictResponse{
+			Message: artist.ErrDuplicateTitles.Error(),
+			Titles:  conflict.Titles,
+		})
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, "Error merging artists")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, merged)
+}
+
+// handleError maps repository errors to HTTP responses
+func (h *artistHandlers) handleError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, artist.ErrNotFound):
+		http.Error(w, "Artist not found", http.StatusNotFound)
+	case errors.Is(err, artist.ErrAlreadyExists), errors.Is(err, artist.ErrHasSongs), errors.Is(err, artist.ErrDuplicateTitles):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		h.logger.Error(message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+func (h *artistHandlers) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
+
+func parseArtistID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "Artist ID is required", http.StatusBadRequest)
+		return 0, false
+	}
+
+	artistID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, "Invalid artist ID", http.StatusBadRequest)
+		return 0, false
+	}
+
+	return artistID, true
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"musiclib/config"
+	"musiclib/internal/artist"
+	"musiclib/internal/models"
+	"musiclib/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// fakeArtistRepo answers Merge with a fixed result, other methods panic through the nil interface
+type fakeArtistRepo struct {
+	artist.Repository
+	merged   *models.Artist
+	mergeErr error
+}
+
+func (r *fakeArtistRepo) Merge(ctx context.Context, sourceID int, targetID int) (*models.Artist, error) {
+	return r.merged, r.mergeErr
+}
+
+func newTestRouter(repo artist.Repository) *mux.Router {
+	cfg := &config.Config{}
+	cfg.Logger.Level = "fatal"
+	appLogger := logger.NewApiLogger(cfg)
+	appLogger.InitLogger()
+
+	router := mux.NewRouter()
+	MapArtistRoutes(router.PathPrefix("/artists").Subrouter(), NewArtistHandlers(cfg, appLogger, repo))
+	return router
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		repo       *fakeArtistRepo
+		wantStatus int
+		wantTitles []string
+	}{
+		{
+			name:       "merged",
+			body:       `{"sourceId": 2, "targetId": 1}`,
+			repo:       &fakeArtistRepo{merged: &models.Artist{ID: 1, Name: "The Beatles"}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "same artist",
+			body:       `{"sourceId": 1, "targetId": 1}`,
+			repo:       &fakeArtistRepo{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "missing target",
+			body:       `{"sourceId": 2}`,
+			repo:       &fakeArtistRepo{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown artist",
+			body:       `{"sourceId": 2, "targetId": 1}`,
+			repo:       &fakeArtistRepo{mergeErr: artist.ErrNotFound},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "duplicate titles are listed",
+			body:       `{"sourceId": 2, "targetId": 1}`,
+			repo:       &fakeArtistRepo{mergeErr: &artist.TitleConflictError{Titles: []string{"Help!", "Yesterday"}}},
+			wantStatus: http.StatusConflict,
+			wantTitles: []string{"Help!", "Yesterday"},
+		},
+		{
+			name:       "duplicate title created during the merge",
+			body:       `{"sourceId": 2, "targetId": 1}`,
+			repo:       &fakeArtistRepo{mergeErr: artist.ErrDuplicateTitles},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/artists/merge", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			newTestRouter(tt.repo).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("POST /artists/merge = %d %q, want %d", rec.Code, rec.Body.String(), tt.wantStatus)
+			}
+			if tt.wantTitles == nil {
+				return
+			}
+
+			var resp models.MergeConflictResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode conflict: %v", err)
+			}
+			if !reflect.DeepEqual(resp.Titles, tt.wantTitles) {
+				t.Errorf("conflict titles = %v, want %v", resp.Titles, tt.wantTitles)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package http
+
+import (
+	"musiclib/internal/artist"
+
+	"github.com/gorilla/mux"
+)
+
+// Map artists routes
+func MapArtistRoutes(artistsGroup *mux.Router, h artist.Handlers) {
+	artistsGroup.HandleFunc("/list", h.GetList).Methods("GET")
+	artistsGroup.HandleFunc("/merge", h.Merge).Methods("POST")
+	artistsGroup.HandleFunc("/", h.GetByID).Methods("GET")
+	artistsGroup.HandleFunc("/", h.Create).Methods("POST")
+	artistsGroup.HandleFunc("/", h.Update).Methods("PUT")
+	artistsGroup.HandleFunc("/", h.Delete).Methods("DELETE")
+}
@@ -0,0 +1,26 @@
+package artist
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrNotFound        = errors.New("artist not found")
+	ErrAlreadyExists   = errors.New("artist with this name already exists")
+	ErrHasSongs        = errors.New("artist still has songs or albums")
+	ErrDuplicateTitles = errors.New("both artists have songs with the same title")
+)
+
+// TitleConflictError lists the source artist's songs whose titles the target artist already has
+type TitleConflictError struct {
+	Titles []string
+}
+
+func (e *TitleConflictError) Error() string {
+	return ErrDuplicateTitles.Error() + ": " + strings.Join(e.Titles, ", ")
+}
+
+func (e *TitleConflictError) Unwrap() error {
+	return ErrDuplicateTitles
+}
@@ -0,0 +1,17 @@
+package artist
+
+import (
+	"context"
+	"musiclib/internal/models"
+)
+
+// Repository interface
+type Repository interface {
+	GetList(ctx context.Context, limit int, offset int) ([]models.Artist, error)
+	GetByID(ctx context.Context, id int) (*models.Artist, error)
+	GetOrCreate(ctx context.Context, name string) (*models.Artist, error)
+	Create(ctx context.Context, artist *models.Artist) (*models.Artist, error)
+	Update(ctx context.Context, artist *models.Artist) (*models.Artist, error)
+	Delete(ctx context.Context, id int) error
+	Merge(ctx context.Context, sourceID int, targetID int) (*models.Artist, error)
+}
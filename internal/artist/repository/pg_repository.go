@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"musiclib/internal/artist"
+	"musiclib/internal/models"
+	"musiclib/pkg/audit"
+	"musiclib/pkg/db/postgres"
+	"musiclib/pkg/logger"
+	"musiclib/pkg/metrics"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// SourceArtistMerge is the revision source of songs moved to another artist by a merge
+const SourceArtistMerge = "artist_merge"
+
+type artistRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+func NewArtistRepository(db *sqlx.DB, logger logger.Logger) *artistRepository {
+	return &artistRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *artistRepository) GetList(ctx context.Context, limit int, offset int) ([]models.Artist, error) {
+	defer metrics.ObserveQuery("artist", "GetList")()
+	r.logger.Debug("Starting GetList in artist repository", "limit", limit, "offset", offset)
+
+	artists := make([]models.Artist, 0)
+	if err := r.db.SelectContext(ctx, &artists, getArtists, limit, offset); err != nil {
+		r.logger.Debug("Failed to get artists", "error", err)
+		return nil, fmt.Errorf("failed to get artists list: %w", err)
+	}
+
+	r.logger.Debug("Successfully retrieved artists", "count", len(artists))
+	return artists, nil
+}
+
+func (r *artistRepository) GetByID(ctx context.Context, id int) (*models.Artist, error) {
+	defer metrics.ObserveQuery("artist", "GetByID")()
+	r.logger.Debug("Starting GetByID in artist repository", "id", id)
+
+	var a models.Artist
+	if err := r.db.GetContext(ctx, &a, getArtistByID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, artist.ErrNotFound
+		}
+		r.logger.Debug("Failed to get artist", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to get artist: %w", err)
+	}
+
+	return &a, nil
+}
+
+// GetOrCreate returns the artist matching name after normalization, creating it when missing
+func (r *artistRepository) GetOrCreate(ctx context.Context, name string) (*models.Artist, error) {
+	defer metrics.ObserveQuery("artist", "GetOrCreate")()
+	r.logger.Debug("Starting GetOrCreate in artist repository", "name", name)
+
+	var a models.Artist
+	err := r.db.GetContext(ctx, &a, getOrCreateArtist,
+		models.CleanArtistName(name),
+		models.NormalizeArtistName(name),
+	)
+	if err != nil {
+		r.logger.Debug("Failed to resolve artist", "error", err, "name", name)
+		return nil, fmt.Errorf("failed to resolve artist: %w", err)
+	}
+
+	r.logger.Debug("Resolved artist", "id", a.ID, "name", a.Name)
+	return &a, nil
+}
+
+func (r *artistRepository) Create(ctx context.Context, a *models.Artist) (*models.Artist, error) {
+	defer metrics.ObserveQuery("artist", "Create")()
+	r.logger.Debug("Starting Create in artist repository", "name", a.Name)
+
+	var created models.Artist
+	err := r.db.GetContext(ctx, &created, createArtist,
+		models.CleanArtistName(a.Name),
+		models.NormalizeArtistName(a.Name),
+		a.Description,
+	)
+	if err != nil {
+		if postgres.IsUniqueViolation(err) {
+			return nil, artist.ErrAlreadyExists
+		}
+		r.logger.Debug("Failed to create artist", "error", err)
+		return nil, fmt.Errorf("failed to create artist: %w", err)
+	}
+
+	r.logger.Debug("Successfully created artist", "id", created.ID)
+	return &created, nil
+}
+
+func (r *artistRepository) Update(ctx context.Context, a *models.Artist) (*models.Artist, error) {
+	defer metrics.ObserveQuery("artist", "Update")()
+	r.logger.Debug("Starting Update in artist repository", "id", a.ID, "name", a.Name)
+
+	var updated models.Artist
+	err := r.db.GetContext(ctx, &updated, updateArtist,
+		models.CleanArtistName(a.Name),
+		models.NormalizeArtistName(a.Name),
+		a.Description,
+		a.ID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, artist.ErrNotFound
+		}
+		if postgres.IsUniqueViolation(err) {
+			return nil, artist.ErrAlreadyExists
+		}
+		r.logger.Debug("Failed to update artist", "error", err, "id", a.ID)
+		return nil, fmt.Errorf("failed to update artist: %w", err)
+	}
+
+	r.logger.Debug("Successfully updated artist", "id", updated.ID)
+	return &updated, nil
+}
+
+func (r *artistRepository) Delete(ctx context.Context, id int) error {
+	defer metrics.ObserveQuery("artist", "Delete")()
+	r.logger.Debug("Starting Delete in artist repository", "id", id)
+
+	result, err := r.db.ExecContext(ctx, deleteArtist, id)
+	if err != nil {
+		if postgres.IsForeignKeyViolation(err) {
+			return artist.ErrHasSongs
+		}
+		r.logger.Debug("Failed to delete artist", "error", err, "id", id)
+		return fmt.Errorf("failed to delete artist: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return artist.ErrNotFound
+	}
+
+	r.logger.Debug("Successfully deleted artist", "id", id)
+	return nil
+}
+
+// Merge moves everything owned by the source artist to the target and removes the source
+func (r *artistRepository) Merge(ctx context.Context, sourceID int, targetID int) (*models.Artist, error) {
+	defer metrics.ObserveQuery("artist", "Merge")()
+	r.logger.Debug("Starting Merge in artist repository", "sourceID", sourceID, "targetID", targetID)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Лочим оба артиста в одном порядке, чтобы встречные слияния не словили дедлок
+	first, second := sourceID, targetID
+	if first > second {
+		first, second = second, first
+	}
+	for _, id := range []int{first, second} {
+		var lockedID int
+		if err := tx.GetContext(ctx, &lockedID, lockArtist, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, artist.ErrNotFound
+			}
+			return nil, fmt.Errorf("failed to lock artist: %w", err)
+		}
+	}
+
+	// Only one active song per title is allowed for an artist, the client decides which one to keep
+	var titles []string
+	if err := tx.SelectContext(ctx, &titles, findDuplicateTitles, targetID, sourceID); err != nil {
+		return nil, fmt.Errorf("failed to check song titles: %w", err)
+	}
+	if len(titles) > 0 {
+		return nil, &artist.TitleConflictError{Titles: titles}
+	}
+
+	var movedIDs []int64
+	if err := tx.SelectContext(ctx, &movedIDs, moveArtistSongs, targetID, sourceID); err != nil {
+		r.logger.Debug("Failed to move songs", "error", err)
+		if postgres.IsUniqueViolation(err) {
+			return nil, artist.ErrDuplicateTitles
+		}
+		return nil, fmt.Errorf("failed to move songs: %w", err)
+	}
+	if err := r.recordMovedSongs(ctx, tx, movedIDs); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, moveArtistAlbums, targetID, sourceID); err != nil {
+		r.logger.Debug("Failed to move albums", "error", err)
+		return nil, fmt.Errorf("failed to move albums: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, deleteArtist, sourceID); err != nil {
+		r.logger.Debug("Failed to delete merged artist", "error", err)
+		return nil, fmt.Errorf("failed to delete merged artist: %w", err)
+	}
+
+	var merged models.Artist
+	if err := tx.GetContext(ctx, &merged, getArtistByID, targetID); err != nil {
+		return nil, fmt.Errorf("failed to get merged artist: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit merge: %w", err)
+	}
+
+	r.logger.Debug("Successfully merged artists", "sourceID", sourceID, "targetID", targetID)
+	return &merged, nil
+}
+
+// recordMovedSongs writes an update revision for every song a merge moved to the target artist
+func (r *artistRepository) recordMovedSongs(ctx context.Context, tx *sqlx.Tx, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var songs []models.Song
+	if err := tx.SelectContext(ctx, &songs, getMovedSongs, pq.Array(ids)); err != nil {
+		return fmt.Errorf("failed to get moved songs: %w", err)
+	}
+
+	for _, song := range songs {
+		snapshot, err := json.Marshal(song)
+		if err != nil {
+			return fmt.Errorf("failed to marshal song snapshot: %w", err)
+		}
+		_, err = tx.ExecContext(ctx, insertSongRevision, song.ID, models.RevisionUpdate, snapshot, audit.Actor(ctx), SourceArtistMerge)
+		if err != nil {
+			r.logger.Debug("Failed to insert song revision", "error", err, "id", song.ID)
+			return fmt.Errorf("failed to insert song revision: %w", err)
+		}
+	}
+	return nil
+}
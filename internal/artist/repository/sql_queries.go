@@ -0,0 +1,62 @@
+package repository
+
+const artistColumns = `a.id, a.name, a.description, a.created_at, a.updated_at,
+    (SELECT COUNT(*) FROM songs s WHERE s.artist_id = a.id AND s.deleted_at IS NULL) AS songs_count`
+
+const getArtists = `SELECT ` + artistColumns + ` FROM artists a ORDER BY a.name, a.id LIMIT $1 OFFSET $2`
+
+const getArtistByID = `SELECT ` + artistColumns + ` FROM artists a WHERE a.id = $1`
+
+const getOrCreateArtist = `
+    INSERT INTO artists (name, normalized_name)
+    VALUES ($1, $2)
+    ON CONFLICT (normalized_name) DO UPDATE SET normalized_name = EXCLUDED.normalized_name
+    RETURNING id, name, description, created_at, updated_at`
+
+const createArtist = `
+    INSERT INTO artists (name, normalized_name, description)
+    VALUES ($1, $2, $3)
+    RETURNING id, name, description, created_at, updated_at`
+
+const updateArtist = `
+    UPDATE artists
+    SET name = $1,
+        normalized_name = $2,
+        description = $3,
+        updated_at = NOW()
+    WHERE id = $4
+    RETURNING id, name, description, created_at, updated_at`
+
+const deleteArtist = `DELETE FROM artists WHERE id = $1`
+
+const lockArtist = `SELECT id FROM artists WHERE id = $1 FOR UPDATE`
+
+// findDuplicateTitles returns the source's songs that would collide with the target's after a merge
+const findDuplicateTitles = `
+    SELECT s.song
+    FROM songs s
+    JOIN songs t ON t.artist_id = $1 AND t.normalized_title = s.normalized_title AND t.deleted_at IS NULL
+    WHERE s.artist_id = $2 AND s.deleted_at IS NULL
+    ORDER BY s.normalized_title, s.id`
+
+const moveArtistSongs = `UPDATE songs SET artist_id = $1 WHERE artist_id = $2 RETURNING id`
+
+// getMovedSongs reads the moved songs the way the song repository snapshots them in revisions
+const getMovedSongs = `
+    SELECT s.id, COALESCE(s.artist_id, 0) AS artist_id, COALESCE(a.name, '') AS group_name,
+           COALESCE(s.song, '') AS song, format_release_date(s.release_date, s.release_date_precision) AS release_date,
+           COALESCE(s.text, '') AS text, COALESCE(s.link, '') AS link, COALESCE(s.album_id, 0) AS album_id,
+           COALESCE(s.disc_number, 0) AS disc_number, COALESCE(s.track_number, 0) AS track_number, s.deleted_at,
+           s.enrichment_status, s.enrichment_error
+    FROM songs s
+    LEFT JOIN artists a ON a.id = s.artist_id
+    WHERE s.id = ANY($1)
+    ORDER BY s.id`
+
+const insertSongRevision = `
+    INSERT INTO song_revisions (song_id, revision, action, snapshot, actor, source)
+    SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5
+    FROM song_revisions
+    WHERE song_id = $1`
+
+const moveArtistAlbums = `UPDATE albums SET artist_id = $1, updated_at = NOW() WHERE artist_id = $2`
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"musiclib/config"
+	"musiclib/internal/models"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// minAdminPasswordLength matches the shortest password accepted at registration
+const minAdminPasswordLength = 8
+
+// EnsureAdmin creates the initial admin from auth.admin_username and auth.admin_password when the
+// library has no admin yet. It returns nil without a user when an admin exists or no password is set
+func EnsureAdmin(ctx context.Context, cfg config.AuthConfig, repo Repository) (*models.User, error) {
+	if cfg.AdminPassword == "" {
+		return nil, nil
+	}
+	if cfg.AdminUsername == "" {
+		return nil, errors.New("auth.admin_username must be set with auth.admin_password")
+	}
+	if len(cfg.AdminPassword) < minAdminPasswordLength {
+		return nil, fmt.Errorf("auth.admin_password must be at least %d bytes long", minAdminPasswordLength)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(cfg.AdminPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash admin password: %w", err)
+	}
+
+	admin, err := repo.CreateAdmin(ctx, &models.User{
+		Username:     cfg.AdminUsername,
+		PasswordHash: string(hash),
+	})
+	if errors.Is(err, ErrAdminExists) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create admin %q: %w", cfg.AdminUsername, err)
+	}
+	return admin, nil
+}
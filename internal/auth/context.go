@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"context"
+	"musiclib/internal/models"
+)
+
+type contextKey int
+
+const principalKey contextKey = iota
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller
+func WithPrincipal(ctx context.Context, p models.Principal) context.Context {
+	return context.WithValue(ctx, principalKey, p)
+}
+
+// PrincipalFrom returns the authenticated caller stored in ctx
+func PrincipalFrom(ctx context.Context) (models.Principal, bool) {
+	p, ok := ctx.Value(principalKey).(models.Principal)
+	return p, ok
+}
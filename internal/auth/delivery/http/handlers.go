@@ -0,0 +1,355 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"musiclib/config"
+	"musiclib/internal/auth"
+	"musiclib/internal/models"
+	"musiclib/pkg/logger"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const defaultLimit = "10"
+const defaultOffset = "0"
+
+const tokenType = "Bearer"
+
+// bcrypt ignores everything past 72 bytes, longer passwords are rejected instead of silently truncated
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{3,64}$`)
+
+// Auth handlers
+type authHandlers struct {
+	cfg      *config.Config
+	authRepo auth.Repository
+	tokens   *auth.Tokens
+	logger   logger.Logger
+	// dummyHash is compared against when the username is unknown, so a failed
+	// login takes as long whether or not the user exists
+	dummyHash []byte
+}
+
+// NewAuthHandlers Auth handlers constructor
+func NewAuthHandlers(cfg *config.Config, logger logger.Logger, repo auth.Repository, tokens *auth.Tokens) *authHandlers {
+	dummyHash, err := bcrypt.GenerateFromPassword([]byte("not a real password"), bcrypt.DefaultCost)
+	if err != nil {
+		logger.Error("Failed to generate dummy password hash", err)
+	}
+	return &authHandlers{cfg: cfg, logger: logger, authRepo: repo, tokens: tokens, dummyHash: dummyHash}
+}
+
+// @Summary     Register
+// @Description Create a viewer account. Unless auth.allow_registration is set, only admins may create accounts.
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       request body models.CredentialsRequest true "Credentials"
+// @Success     201 {object} models.User
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     401 {object} models.ErrorResponse
+// @Failure     403 {object} models.ErrorResponse
+// @Failure     409 {object} models.ErrorResponse
+// @Failure     429 {object} models.RateLimitResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /auth/register [post]
+func (h *authHandlers) Register(w http.ResponseWriter, r *http.Request) {
+	var req models.CredentialsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if !usernamePattern.MatchString(req.Username) {
+		http.Error(w, "Username must be 3 to 64 letters, digits, dots, dashes or underscores", http.StatusBadRequest)
+		return
+	}
+	if len(req.Password) < minPasswordLength || len(req.Password) > maxPasswordLength {
+		http.Error(w, "Password must be 8 to 72 bytes long", http.StatusBadRequest)
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		h.logger.Error("Error hashing password", err)
+		http.Error(w, "Error creating user", http.StatusInternalServerError)
+		return
+	}
+
+	created, err := h.authRepo.Create(r.Context(), &models.User{
+		Username:     req.Username,
+		PasswordHash: string(hash),
+	})
+	if err != nil {
+		h.handleError(w, err, "Error creating user")
+		return
+	}
+
+	h.logger.Info("User registered", "id", created.ID, "username", created.Username, "role", created.Role)
+	h.writeJSON(w, http.StatusCreated, created)
+}
+
+// @Summary     Log in
+// @Description Exchange credentials for an access token and a refresh token
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       request body models.CredentialsRequest true "Credentials"
+// @Success     200 {object} models.TokenResponse
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     401 {object} models.ErrorResponse
+// @Failure     429 {object} models.RateLimitResponse
+// @Router      /auth/login [post]
+func (h *authHandlers) Login(w http.ResponseWriter, r *http.Request) {
+	var req models.CredentialsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.authRepo.GetByUsername(r.Context(), req.Username)
+	if err != nil && !errors.Is(err, auth.ErrNotFound) {
+		h.handleError(w, err, "Error logging in")
+		return
+	}
+
+	hash := h.dummyHash
+	if user != nil {
+		hash = []byte(user.PasswordHash)
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(req.Password)); err != nil || user == nil {
+		h.handleError(w, auth.ErrInvalidCredentials, "Error logging in")
+		return
+	}
+
+	token, hashed, expiresAt, err := h.tokens.NewRefreshToken()
+	if err != nil {
+		h.handleError(w, err, "Error logging in")
+		return
+	}
+	if err := h.authRepo.SaveRefreshToken(r.Context(), user.ID, hashed, expiresAt); err != nil {
+		h.handleError(w, err, "Error logging in")
+		return
+	}
+
+	h.writeTokens(w, user, token)
+}
+
+// @Summary     Refresh tokens
+// @Description Exchange a refresh token for a new token pair, the old refresh token stops working
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       request body models.RefreshTokenRequest true "Refresh token"
+// @Success     200 {object} models.TokenResponse
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     401 {object} models.ErrorResponse
+// @Failure     429 {object} models.RateLimitResponse
+// @Router      /auth/refresh [post]
+func (h *authHandlers) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	token, hashed, expiresAt, err := h.tokens.NewRefreshToken()
+	if err != nil {
+		h.handleError(w, err, "Error refreshing tokens")
+		return
+	}
+
+	user, err := h.authRepo.RotateRefreshToken(r.Context(), auth.HashRefreshToken(req.RefreshToken), hashed, expiresAt)
+	if err != nil {
+		h.handleError(w, err, "Error refreshing tokens")
+		return
+	}
+
+	h.writeTokens(w, user, token)
+}
+
+// @Summary     Log out
+// @Description Revoke a refresh token, access tokens stay valid until they expire
+// @Tags        auth
+// @Accept      json
+// @Produce     plain
+// @Param       request body models.RefreshTokenRequest true "Refresh token"
+// @Success     204
+// @Failure     400 {object} models.ErrorResponse
+// @Router      /auth/logout [post]
+func (h *authHandlers) Logout(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authRepo.RevokeRefreshToken(r.Context(), auth.HashRefreshToken(req.RefreshToken)); err != nil {
+		h.handleError(w, err, "Error logging out")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary     Current user
+// @Description Get the signed in user
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Success     200 {object} models.User
+// @Failure     401 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Router      /auth/me [get]
+func (h *authHandlers) Me(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.PrincipalFrom(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authRepo.GetByID(r.Context(), principal.UserID)
+	if err != nil {
+		h.handleError(w, err, "Error getting user")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, user)
+}
+
+// @Summary     List users
+// @Description Get paginated list of users ordered by username, admins only
+// @Tags        users
+// @Accept      json
+// @Produce     json
+// @Param       limit query int false "Number of items to return (default: 10)"
+// @Param       offset query int false "Number of items to skip (default: 0)"
+// @Success     200 {array} models.User
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     403 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /users [get]
+func (h *authHandlers) GetUsers(w http.ResponseWriter, r *http.Request) {
+	limit := r.URL.Query().Get("limit")
+	offset := r.URL.Query().Get("offset")
+
+	if limit == "" {
+		limit = defaultLimit
+	}
+	if offset == "" {
+		offset = defaultOffset
+	}
+
+	limitInt, err := strconv.Atoi(limit)
+	if err != nil {
+		http.Error(w, "Invalid limit value", http.StatusBadRequest)
+		return
+	}
+	offsetInt, err := strconv.Atoi(offset)
+	if err != nil {
+		http.Error(w, "Invalid offset value", http.StatusBadRequest)
+		return
+	}
+
+	users, err := h.authRepo.GetList(r.Context(), limitInt, offsetInt)
+	if err != nil {
+		h.logger.Error("Error getting users from repository", err)
+		http.Error(w, "Error getting users", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, users)
+}
+
+// @Summary     Change role
+// @Description Change the role of a user, admins only. The user is signed out of all sessions.
+// @Tags        users
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "User ID"
+// @Param       request body models.UpdateRoleRequest true "Role request"
+// @Success     200 {object} models.User
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     403 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Failure     409 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /users/{id}/role [put]
+func (h *authHandlers) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !models.IsValidRole(req.Role) {
+		http.Error(w, "Role must be viewer, editor or admin", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.authRepo.UpdateRole(r.Context(), userID, req.Role)
+	if err != nil {
+		h.handleError(w, err, "Error updating role")
+		return
+	}
+
+	h.logger.Info("User role changed", "id", updated.ID, "role", updated.Role)
+	h.writeJSON(w, http.StatusOK, updated)
+}
+
+// writeTokens issues an access token for the user and sends it with the refresh token
+func (h *authHandlers) writeTokens(w http.ResponseWriter, user *models.User, refreshToken string) {
+	accessToken, err := h.tokens.IssueAccessToken(user)
+	if err != nil {
+		h.handleError(w, err, "Error issuing tokens")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.writeJSON(w, http.StatusOK, models.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    tokenType,
+		ExpiresIn:    int(h.tokens.AccessTTL().Seconds()),
+	})
+}
+
+// handleError maps repository errors to HTTP responses
+func (h *authHandlers) handleError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, auth.ErrNotFound):
+		http.Error(w, "User not found", http.StatusNotFound)
+	case errors.Is(err, auth.ErrInvalidCredentials), errors.Is(err, auth.ErrInvalidToken):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, auth.ErrAlreadyExists), errors.Is(err, auth.ErrLastAdmin):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		h.logger.Error(message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+func (h *authHandlers) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,180 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"musiclib/config"
+	"musiclib/internal/auth"
+	"musiclib/internal/models"
+	"musiclib/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeAuthRepo knows one user, methods a test does not need panic through the nil interface
+type fakeAuthRepo struct {
+	auth.Repository
+	user    *models.User
+	created *models.User
+	saved   string
+}
+
+func (r *fakeAuthRepo) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	if r.user == nil || models.NormalizeUsername(username) != models.NormalizeUsername(r.user.Username) {
+		return nil, auth.ErrNotFound
+	}
+	return r.user, nil
+}
+
+func (r *fakeAuthRepo) SaveRefreshToken(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	r.saved = tokenHash
+	return nil
+}
+
+func (r *fakeAuthRepo) Create(ctx context.Context, user *models.User) (*models.User, error) {
+	if r.user != nil && models.NormalizeUsername(user.Username) == models.NormalizeUsername(r.user.Username) {
+		return nil, auth.ErrAlreadyExists
+	}
+	created := *user
+	created.ID = 2
+	created.Role = models.RoleViewer
+	r.created = &created
+	return &created, nil
+}
+
+func newTestRouter(t *testing.T, repo auth.Repository, register mux.MiddlewareFunc) (*mux.Router, *auth.Tokens) {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Logger.Level = "fatal"
+	appLogger := logger.NewApiLogger(cfg)
+	appLogger.InitLogger()
+
+	tokens, err := auth.NewTokens(config.AuthConfig{
+		JWTSecret:       "0123456789abcdef0123456789abcdef",
+		Issuer:          "musiclib",
+		AccessTokenTTL:  time.Minute,
+		RefreshTokenTTL: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewTokens: %v", err)
+	}
+
+	pass := func(next http.Handler) http.Handler { return next }
+	if register == nil {
+		register = pass
+	}
+
+	router := mux.NewRouter()
+	MapAuthRoutes(router.PathPrefix("/auth").Subrouter(), NewAuthHandlers(cfg, appLogger, repo, tokens), pass, register)
+	return router, tokens
+}
+
+func post(router http.Handler, target string, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, strings.NewReader(body)))
+	return rec
+}
+
+func TestLogin(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	repo := &fakeAuthRepo{user: &models.User{ID: 1, Username: "dj_anna", Role: models.RoleEditor, PasswordHash: string(hash)}}
+	router, tokens := newTestRouter(t, repo, nil)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"valid credentials", `{"username": "dj_anna", "password": "correct horse"}`, http.StatusOK},
+		{"username is case-insensitive", `{"username": "DJ_Anna", "password": "correct horse"}`, http.StatusOK},
+		{"wrong password", `{"username": "dj_anna", "password": "battery staple"}`, http.StatusUnauthorized},
+		{"unknown user", `{"username": "nobody", "password": "correct horse"}`, http.StatusUnauthorized},
+		{"invalid body", `{"username": `, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := post(router, "/auth/login", tt.body)
+			if rec.Code != tt.want {
+				t.Fatalf("POST /auth/login = %d %q, want %d", rec.Code, rec.Body.String(), tt.want)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			var resp models.TokenResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode tokens: %v", err)
+			}
+			principal, err := tokens.ParseAccessToken(resp.AccessToken)
+			if err != nil || principal.UserID != 1 || principal.Role != models.RoleEditor {
+				t.Errorf("access token principal = %+v, %v, want user 1 as editor", principal, err)
+			}
+			if auth.HashRefreshToken(resp.RefreshToken) != repo.saved {
+				t.Error("refresh token hash was not saved")
+			}
+			if rec.Header().Get("Cache-Control") != "no-store" {
+				t.Errorf("Cache-Control = %q, want no-store", rec.Header().Get("Cache-Control"))
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"viewer account", `{"username": "new_user", "password": "long enough"}`, http.StatusCreated},
+		{"taken username", `{"username": "DJ_ANNA", "password": "long enough"}`, http.StatusConflict},
+		{"short username", `{"username": "ab", "password": "long enough"}`, http.StatusBadRequest},
+		{"short password", `{"username": "new_user", "password": "short"}`, http.StatusBadRequest},
+		{"password past the bcrypt limit", `{"username": "new_user", "password": "` + strings.Repeat("x", 73) + `"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAuthRepo{user: &models.User{ID: 1, Username: "dj_anna"}}
+			router, _ := newTestRouter(t, repo, nil)
+
+			rec := post(router, "/auth/register", tt.body)
+			if rec.Code != tt.want {
+				t.Fatalf("POST /auth/register = %d %q, want %d", rec.Code, rec.Body.String(), tt.want)
+			}
+			if tt.want == http.StatusCreated && repo.created.Role != models.RoleViewer {
+				t.Errorf("created role = %q, want viewer", repo.created.Role)
+			}
+		})
+	}
+}
+
+func TestRegisterClosed(t *testing.T) {
+	repo := &fakeAuthRepo{}
+	closed := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "Insufficient role, admin required", http.StatusForbidden)
+		})
+	}
+	router, _ := newTestRouter(t, repo, closed)
+
+	rec := post(router, "/auth/register", `{"username": "new_user", "password": "long enough"}`)
+	if rec.Code != http.StatusForbidden || repo.created != nil {
+		t.Errorf("POST /auth/register = %d, created %v, want 403 and no account", rec.Code, repo.created)
+	}
+
+	// Only registration is guarded, signing in stays open
+	rec = post(router, "/auth/login", `{"username": `)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("POST /auth/login = %d, want 400 from the handler", rec.Code)
+	}
+}
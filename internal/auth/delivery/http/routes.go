@@ -0,0 +1,31 @@
+package http
+
+import (
+	"musiclib/internal/auth"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Route names, rate limits are configured per route name
+const (
+	RouteRegister = "auth_register"
+	RouteLogin    = "auth_login"
+	RouteRefresh  = "auth_refresh"
+)
+
+// Map auth routes, authenticate guards the routes that need a signed in user
+// and register decides who may create accounts
+func MapAuthRoutes(authGroup *mux.Router, h auth.Handlers, authenticate mux.MiddlewareFunc, register mux.MiddlewareFunc) {
+	authGroup.Handle("/register", register(http.HandlerFunc(h.Register))).Methods("POST").Name(RouteRegister)
+	authGroup.HandleFunc("/login", h.Login).Methods("POST").Name(RouteLogin)
+	authGroup.HandleFunc("/refresh", h.Refresh).Methods("POST").Name(RouteRefresh)
+	authGroup.HandleFunc("/logout", h.Logout).Methods("POST")
+	authGroup.Handle("/me", authenticate(http.HandlerFunc(h.Me))).Methods("GET")
+}
+
+// Map users routes
+func MapUserRoutes(usersGroup *mux.Router, h auth.Handlers) {
+	usersGroup.HandleFunc("", h.GetUsers).Methods("GET")
+	usersGroup.HandleFunc("/{id:[0-9]+}/role", h.UpdateRole).Methods("PUT")
+}
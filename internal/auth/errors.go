@@ -0,0 +1,12 @@
+package auth
+
+import "errors"
+
+var (
+	ErrNotFound           = errors.New("user not found")
+	ErrAlreadyExists      = errors.New("user with this username already exists")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrLastAdmin          = errors.New("cannot demote the last admin")
+	ErrAdminExists        = errors.New("an admin already exists")
+)
@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+	"musiclib/internal/models"
+	"time"
+)
+
+// Repository interface
+type Repository interface {
+	Create(ctx context.Context, user *models.User) (*models.User, error)
+	CreateAdmin(ctx context.Context, user *models.User) (*models.User, error)
+	GetByID(ctx context.Context, id int) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	GetList(ctx context.Context, limit int, offset int) ([]models.User, error)
+	UpdateRole(ctx context.Context, id int, role string) (*models.User, error)
+	SaveRefreshToken(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error
+	RotateRefreshToken(ctx context.Context, tokenHash string, newHash string, expiresAt time.Time) (*models.User, error)
+	RevokeRefreshToken(ctx context.Context, tokenHash string) error
+}
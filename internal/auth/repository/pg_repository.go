@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"musiclib/internal/auth"
+	"musiclib/internal/models"
+	"musiclib/pkg/db/postgres"
+	"musiclib/pkg/logger"
+	"musiclib/pkg/metrics"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type authRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+func NewAuthRepository(db *sqlx.DB, logger logger.Logger) *authRepository {
+	return &authRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create registers the user as a viewer
+func (r *authRepository) Create(ctx context.Context, u *models.User) (*models.User, error) {
+	defer metrics.ObserveQuery("auth", "Create")()
+	r.logger.Debug("Starting Create in auth repository", "username", u.Username)
+
+	created, err := r.create(ctx, r.db, u, models.RoleViewer)
+	if err != nil {
+		r.logger.Debug("Failed to create user", "error", err)
+		return nil, err
+	}
+
+	r.logger.Debug("Successfully created user", "id", created.ID, "role", created.Role)
+	return created, nil
+}
+
+// CreateAdmin creates u as an admin unless there already is one, then it returns auth.ErrAdminExists
+func (r *authRepository) CreateAdmin(ctx context.Context, u *models.User) (*models.User, error) {
+	defer metrics.ObserveQuery("auth", "CreateAdmin")()
+	r.logger.Debug("Starting CreateAdmin in auth repository", "username", u.Username)
+
+	var created *models.User
+	err := r.inTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, lockUsers); err != nil {
+			return fmt.Errorf("failed to lock users: %w", err)
+		}
+
+		var exists bool
+		if err := tx.GetContext(ctx, &exists, adminExists); err != nil {
+			return fmt.Errorf("failed to check admins: %w", err)
+		}
+		if exists {
+			return auth.ErrAdminExists
+		}
+
+		var err error
+		created, err = r.create(ctx, tx, u, models.RoleAdmin)
+		return err
+	})
+	if err != nil {
+		r.logger.Debug("Failed to create admin", "error", err)
+		return nil, err
+	}
+
+	r.logger.Debug("Successfully created admin", "id", created.ID)
+	return created, nil
+}
+
+func (r *authRepository) create(ctx context.Context, q sqlx.QueryerContext, u *models.User, role string) (*models.User, error) {
+	var created models.User
+	err := sqlx.GetContext(ctx, q, &created, createUser,
+		u.Username,
+		models.NormalizeUsername(u.Username),
+		u.PasswordHash,
+		role,
+	)
+	if err != nil {
+		if postgres.IsUniqueViolation(err) {
+			return nil, auth.ErrAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	return &created, nil
+}
+
+func (r *authRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
+	defer metrics.ObserveQuery("auth", "GetByID")()
+	r.logger.Debug("Starting GetByID in auth repository", "id", id)
+	return r.getUser(ctx, r.db, getUserByID, id)
+}
+
+func (r *authRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	defer metrics.ObserveQuery("auth", "GetByUsername")()
+	r.logger.Debug("Starting GetByUsername in auth repository", "username", username)
+	return r.getUser(ctx, r.db, getUserByUsername, models.NormalizeUsername(username))
+}
+
+func (r *authRepository) GetList(ctx context.Context, limit int, offset int) ([]models.User, error) {
+	defer metrics.ObserveQuery("auth", "GetList")()
+	r.logger.Debug("Starting GetList in auth repository", "limit", limit, "offset", offset)
+
+	users := make([]models.User, 0)
+	if err := r.db.SelectContext(ctx, &users, getUsers, limit, offset); err != nil {
+		r.logger.Debug("Failed to get users", "error", err)
+		return nil, fmt.Errorf("failed to get users list: %w", err)
+	}
+
+	r.logger.Debug("Successfully retrieved users", "count", len(users))
+	return users, nil
+}
+
+// UpdateRole changes the user's role and signs them out everywhere, so the new role
+// applies as soon as their current access tokens expire
+func (r *authRepository) UpdateRole(ctx context.Context, id int, role string) (*models.User, error) {
+	defer metrics.ObserveQuery("auth", "UpdateRole")()
+	r.logger.Debug("Starting UpdateRole in auth repository", "id", id, "role", role)
+
+	var updated models.User
+	err := r.inTx(ctx, func(tx *sqlx.Tx) error {
+		var admins []int
+		if err := tx.SelectContext(ctx, &admins, lockAdmins); err != nil {
+			return fmt.Errorf("failed to lock admins: %w", err)
+		}
+		if role != models.RoleAdmin && len(admins) == 1 && admins[0] == id {
+			return auth.ErrLastAdmin
+		}
+
+		if err := tx.GetContext(ctx, &updated, updateUserRole, role, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return auth.ErrNotFound
+			}
+			return fmt.Errorf("failed to update role: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, revokeUserRefreshTokens, id); err != nil {
+			return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Debug("Failed to update role", "error", err, "id", id)
+		return nil, err
+	}
+
+	r.logger.Debug("Successfully updated role", "id", id, "role", role)
+	return &updated, nil
+}
+
+func (r *authRepository) SaveRefreshToken(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	defer metrics.ObserveQuery("auth", "SaveRefreshToken")()
+	r.logger.Debug("Starting SaveRefreshToken in auth repository", "userID", userID)
+
+	if _, err := r.db.ExecContext(ctx, insertRefreshToken, userID, tokenHash, expiresAt); err != nil {
+		r.logger.Debug("Failed to save refresh token", "error", err)
+		return fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	return nil
+}
+
+// RotateRefreshToken revokes the token and stores its replacement, it returns the token owner
+func (r *authRepository) RotateRefreshToken(ctx context.Context, tokenHash string, newHash string, expiresAt time.Time) (*models.User, error) {
+	defer metrics.ObserveQuery("auth", "RotateRefreshToken")()
+	r.logger.Debug("Starting RotateRefreshToken in auth repository")
+
+	var user *models.User
+	err := r.inTx(ctx, func(tx *sqlx.Tx) error {
+		var userID int
+		if err := tx.GetContext(ctx, &userID, consumeRefreshToken, tokenHash); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return auth.ErrInvalidToken
+			}
+			return fmt.Errorf("failed to consume refresh token: %w", err)
+		}
+
+		var err error
+		if user, err = r.getUser(ctx, tx, getUserByID, userID); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, insertRefreshToken, userID, newHash, expiresAt); err != nil {
+			return fmt.Errorf("failed to save refresh token: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Debug("Failed to rotate refresh token", "error", err)
+		return nil, err
+	}
+	return user, nil
+}
+
+// RevokeRefreshToken signs the token out, unknown and already revoked tokens are ignored
+func (r *authRepository) RevokeRefreshToken(ctx context.Context, tokenHash string) error {
+	defer metrics.ObserveQuery("auth", "RevokeRefreshToken")()
+	r.logger.Debug("Starting RevokeRefreshToken in auth repository")
+
+	if _, err := r.db.ExecContext(ctx, revokeRefreshToken, tokenHash); err != nil {
+		r.logger.Debug("Failed to revoke refresh token", "error", err)
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	return nil
+}
+
+func (r *authRepository) getUser(ctx context.Context, q sqlx.QueryerContext, query string, arg interface{}) (*models.User, error) {
+	var u models.User
+	if err := sqlx.GetContext(ctx, q, &u, query, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, auth.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return &u, nil
+}
+
+func (r *authRepository) inTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
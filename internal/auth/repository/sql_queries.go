@@ -0,0 +1,43 @@
+package repository
+
+const userColumns = `id, username, role, password_hash, created_at, updated_at`
+
+// lockUsers keeps concurrently starting instances from both creating the initial admin
+const lockUsers = `LOCK TABLE users IN SHARE ROW EXCLUSIVE MODE`
+
+const createUser = `
+    INSERT INTO users (username, normalized_username, password_hash, role)
+    VALUES ($1, $2, $3, $4)
+    RETURNING ` + userColumns
+
+const adminExists = `SELECT EXISTS (SELECT 1 FROM users WHERE role = 'admin')`
+
+const getUserByID = `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+
+const getUserByUsername = `SELECT ` + userColumns + ` FROM users WHERE normalized_username = $1`
+
+const getUsers = `SELECT ` + userColumns + ` FROM users ORDER BY normalized_username, id LIMIT $1 OFFSET $2`
+
+const lockAdmins = `SELECT id FROM users WHERE role = 'admin' FOR UPDATE`
+
+const updateUserRole = `
+    UPDATE users
+    SET role = $1,
+        updated_at = NOW()
+    WHERE id = $2
+    RETURNING ` + userColumns
+
+const insertRefreshToken = `
+    INSERT INTO refresh_tokens (user_id, token_hash, expires_at)
+    VALUES ($1, $2, $3)`
+
+// consumeRefreshToken revokes a live token and returns its owner, a token can be used only once
+const consumeRefreshToken = `
+    UPDATE refresh_tokens
+    SET revoked_at = NOW()
+    WHERE token_hash = $1 AND revoked_at IS NULL AND expires_at > NOW()
+    RETURNING user_id`
+
+const revokeRefreshToken = `UPDATE refresh_tokens SET revoked_at = NOW() WHERE token_hash = $1 AND revoked_at IS NULL`
+
+const revokeUserRefreshTokens = `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"musiclib/config"
+	"musiclib/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minSecretLength is the shortest HS256 secret accepted, shorter keys can be brute-forced
+const minSecretLength = 32
+
+const refreshTokenBytes = 32
+
+// accessClaims are the claims of an access token, the subject is the user ID
+type accessClaims struct {
+	jwt.RegisteredClaims
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// Tokens issues and verifies access tokens and generates refresh tokens
+type Tokens struct {
+	secret     []byte
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewTokens(cfg config.AuthConfig) (*Tokens, error) {
+	if len(cfg.JWTSecret) < minSecretLength {
+		return nil, fmt.Errorf("auth.jwt_secret must be at least %d bytes long", minSecretLength)
+	}
+	if cfg.AccessTokenTTL <= 0 || cfg.RefreshTokenTTL <= 0 {
+		return nil, errors.New("auth token lifetimes must be positive")
+	}
+
+	return &Tokens{
+		secret:     []byte(cfg.JWTSecret),
+		issuer:     cfg.Issuer,
+		accessTTL:  cfg.AccessTokenTTL,
+		refreshTTL: cfg.RefreshTokenTTL,
+	}, nil
+}
+
+// AccessTTL is the lifetime of the access tokens
+func (t *Tokens) AccessTTL() time.Duration {
+	return t.accessTTL
+}
+
+// IssueAccessToken signs a short-lived token carrying the user's role
+func (t *Tokens) IssueAccessToken(user *models.User) (string, error) {
+	now := time.Now()
+	claims := accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    t.issuer,
+			Subject:   strconv.Itoa(user.ID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(t.accessTTL)),
+		},
+		Username: user.Username,
+		Role:     user.Role,
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(t.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign access token: %w", err)
+	}
+	return signed, nil
+}
+
+// ParseAccessToken verifies the signature, issuer and expiry of the token
+func (t *Tokens) ParseAccessToken(token string) (models.Principal, error) {
+	var claims accessClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return t.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(t.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return models.Principal{}, ErrInvalidToken
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil || !models.IsValidRole(claims.Role) {
+		return models.Principal{}, ErrInvalidToken
+	}
+
+	return models.Principal{UserID: userID, Username: claims.Username, Role: claims.Role}, nil
+}
+
+// NewRefreshToken returns a random opaque token, its hash and its expiry
+func (t *Tokens) NewRefreshToken() (token string, hash string, expiresAt time.Time, err error) {
+	buf := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", time.Time{}, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashRefreshToken(token), time.Now().Add(t.refreshTTL), nil
+}
+
+// HashRefreshToken returns the form refresh tokens are stored in
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,69 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"musiclib/pkg/db/migrations"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Database checks that a connection to Postgres can be made and answers
+func Database(db *sqlx.DB) Check {
+	return Check{
+		Name:     "database",
+		Critical: true,
+		Probe:    db.PingContext,
+	}
+}
+
+// Migrations checks that the database schema is at the newest migration embedded in the binary
+func Migrations(db *sqlx.DB) Check {
+	return Check{
+		Name:     "migrations",
+		Critical: true,
+		Probe: func(ctx context.Context) error {
+			expected, err := migrations.LatestVersion()
+			if err != nil {
+				return err
+			}
+			version, dirty, err := migrations.Version(ctx, db.DB)
+			if err != nil {
+				return fmt.Errorf("failed to read migration version: %w", err)
+			}
+			if dirty {
+				return fmt.Errorf("migration %d failed and left the database dirty", version)
+			}
+			if version != expected {
+				return fmt.Errorf("database is at migration %d, expected %d", version, expected)
+			}
+			return nil
+		},
+	}
+}
+
+// MusicAPI checks that the music API answers at url. Songs can still be read while it is down,
+// so it is not critical
+func MusicAPI(url string) Check {
+	client := &http.Client{}
+	return Check{
+		Name: "music_api",
+		Probe: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode >= http.StatusInternalServerError {
+				return fmt.Errorf("music API answered with status %d", resp.StatusCode)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,11 @@
+package health
+
+import (
+	"net/http"
+)
+
+// Health HTTP Handlers interface
+type Handlers interface {
+	Healthz(w http.ResponseWriter, r *http.Request)
+	Readyz(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"encoding/json"
+	"musiclib/config"
+	"musiclib/internal/health"
+	"musiclib/internal/models"
+	"musiclib/pkg/logger"
+	"net/http"
+)
+
+// Health handlers
+type healthHandlers struct {
+	cfg     *config.Config
+	checker *health.Checker
+	logger  logger.Logger
+}
+
+// NewHealthHandlers Health handlers constructor
+func NewHealthHandlers(cfg *config.Config, logger logger.Logger, checker *health.Checker) *healthHandlers {
+	return &healthHandlers{cfg: cfg, logger: logger, checker: checker}
+}
+
+// Healthz answers as long as the process is serving requests, it checks no dependency
+func (h *healthHandlers) Healthz(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, models.HealthReport{Status: models.HealthOK})
+}
+
+// Readyz reports every dependency with its latency, 503 when the service should not get traffic:
+// a critical dependency is down or the server is shutting down
+func (h *healthHandlers) Readyz(w http.ResponseWriter, r *http.Request) {
+	report := h.checker.Ready(r.Context())
+
+	status := http.StatusOK
+	if report.Status == models.HealthNotReady || report.Status == models.HealthShuttingDown {
+		status = http.StatusServiceUnavailable
+		h.logger.Warnw("Service is not ready", "status", report.Status, "checks", report.Checks)
+	}
+	h.writeJSON(w, status, report)
+}
+
+func (h *healthHandlers) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
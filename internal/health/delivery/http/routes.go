@@ -0,0 +1,13 @@
+package http
+
+import (
+	"musiclib/internal/health"
+
+	"github.com/gorilla/mux"
+)
+
+// Map the liveness and readiness probes, they sit outside the API and need no credentials
+func MapHealthRoutes(router *mux.Router, h health.Handlers) {
+	router.HandleFunc("/healthz", h.Healthz).Methods("GET")
+	router.HandleFunc("/readyz", h.Readyz).Methods("GET")
+}
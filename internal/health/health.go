@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"musiclib/internal/models"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Check probes one dependency, the service is not ready while a critical one is down
+type Check struct {
+	Name     string
+	Critical bool
+	Probe    func(ctx context.Context) error
+}
+
+// Checker runs the readiness checks and tracks whether the server is shutting down
+type Checker struct {
+	checks       []Check
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+// NewChecker readiness checker constructor, each check gets timeout to answer
+func NewChecker(timeout time.Duration, checks ...Check) *Checker {
+	return &Checker{checks: checks, timeout: timeout}
+}
+
+// SetShuttingDown makes every following readiness report fail, it cannot be undone
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Ready runs the checks concurrently and reports each of them. Nothing is probed once shutdown began
+func (c *Checker) Ready(ctx context.Context) models.HealthReport {
+	if c.shuttingDown.Load() {
+		return models.HealthReport{Status: models.HealthShuttingDown}
+	}
+
+	results := make([]models.HealthCheck, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = c.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := models.HealthReport{Status: models.HealthReady, Checks: results}
+	for _, result := range results {
+		if result.Status == models.HealthUp {
+			continue
+		}
+		if result.Critical {
+			report.Status = models.HealthNotReady
+			break
+		}
+		report.Status = models.HealthDegraded
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) models.HealthCheck {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := check.Probe(ctx)
+	result := models.HealthCheck{
+		Name:      check.Name,
+		Status:    models.HealthUp,
+		Critical:  check.Critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = models.HealthDown
+		result.Error = err.Error()
+	}
+	return result
+}
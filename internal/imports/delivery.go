@@ -0,0 +1,11 @@
+package imports
+
+import (
+	"net/http"
+)
+
+// Import HTTP Handlers interface
+type Handlers interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	GetByID(w http.ResponseWriter, r *http.Request)
+}
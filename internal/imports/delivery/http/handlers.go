@@ -0,0 +1,242 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"musiclib/config"
+	"musiclib/internal/imports"
+	"musiclib/internal/models"
+	"musiclib/pkg/audit"
+	"musiclib/pkg/logger"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+const defaultRowsLimit = "100"
+const defaultOffset = "0"
+
+const (
+	defaultMaxRows       = 10000
+	defaultMaxUploadSize = 10 << 20
+)
+
+// Import handlers
+type importHandlers struct {
+	cfg        *config.Config
+	importRepo imports.Repository
+	logger     logger.Logger
+}
+
+// NewImportHandlers Import handlers constructor
+func NewImportHandlers(cfg *config.Config, logger logger.Logger, importRepo imports.Repository) *importHandlers {
+	return &importHandlers{cfg: cfg, logger: logger, importRepo: importRepo}
+}
+
+// @Summary     Start import
+// @Description Upload a CSV file (header with group, song and optionally text, link, release_date columns)
+// @Description or NDJSON (one {"group", "song", "text", "link", "releaseDate"} object per line). The body may be
+// @Description the raw file or a multipart form with a "file" field. Rows are processed in the background, missing
+// @Description details are fetched from the music API. With dry_run nothing is written, the results tell what would happen.
+// @Tags        imports
+// @Accept      text/csv,application/x-ndjson,multipart/form-data
+// @Produce     json
+// @Param       format query string false "csv or ndjson, detected from the content type or file name when omitted"
+// @Param       dry_run query bool false "Only report what would be created, updated or skipped"
+// @Success     202 {object} models.ImportJob
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     413 {object} models.ErrorResponse
+// @Failure     500 {object} models.ErrorResponse
+// @Failure     429 {object} models.RateLimitResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /imports [post]
+func (h *importHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "Invalid dry_run value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	maxUploadSize := h.cfg.Imports.MaxUploadSize
+	if maxUploadSize <= 0 {
+		maxUploadSize = defaultMaxUploadSize
+	}
+	maxRows := h.cfg.Imports.MaxRows
+	if maxRows <= 0 {
+		maxRows = defaultMaxRows
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+
+	body, format, err := uploadedFile(r)
+	if err != nil {
+		h.handleUploadError(w, err)
+		return
+	}
+	defer body.Close()
+
+	rows, err := parseRows(body, format, maxRows)
+	if err != nil {
+		h.handleUploadError(w, err)
+		return
+	}
+
+	job, err := h.importRepo.Create(r.Context(), &models.ImportJob{
+		Format: format,
+		DryRun: dryRun,
+		Actor:  audit.Actor(r.Context()),
+	}, rows)
+	if err != nil {
+		h.logger.Error("Error creating import job", err)
+		http.Error(w, "Error creating import job", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/imports/%d", job.ID))
+	h.writeJSON(w, http.StatusAccepted, job)
+}
+
+// @Summary     Get import
+// @Description Get the progress of an import job with a page of its row results
+// @Tags        imports
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Import job ID"
+// @Param       status query string false "Only rows in this status: pending, done or failed"
+// @Param       limit query int false "Number of rows to return (default: 100)"
+// @Param       offset query int false "Number of rows to skip (default: 0)"
+// @Success     200 {object} models.ImportJobDetail
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /imports/{id} [get]
+func (h *importHandlers) GetByID(w http.ResponseWriter, r *http.Request) {
+	jobID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid import ID", http.StatusBadRequest)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status != "" && status != models.ImportRowPending && status != models.ImportRowDone && status != models.ImportRowFailed {
+		http.Error(w, "Invalid status value", http.StatusBadRequest)
+		return
+	}
+
+	limit := r.URL.Query().Get("limit")
+	offset := r.URL.Query().Get("offset")
+
+	if limit == "" {
+		limit = defaultRowsLimit
+	}
+	if offset == "" {
+		offset = defaultOffset
+	}
+
+	limitInt, err := strconv.Atoi(limit)
+	if err != nil {
+		http.Error(w, "Invalid limit value", http.StatusBadRequest)
+		return
+	}
+	offsetInt, err := strconv.Atoi(offset)
+	if err != nil {
+		http.Error(w, "Invalid offset value", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.importRepo.GetByID(r.Context(), jobID)
+	if err != nil {
+		if errors.Is(err, imports.ErrNotFound) {
+			http.Error(w, "Import not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Error getting import job", err)
+		http.Error(w, "Error getting import job", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := h.importRepo.GetRows(r.Context(), jobID, status, limitInt, offsetInt)
+	if err != nil {
+		h.logger.Error("Error getting import rows", err)
+		http.Error(w, "Error getting import rows", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, models.ImportJobDetail{ImportJob: *job, Rows: rows})
+}
+
+// uploadedFile returns the file from a multipart form or the raw body, with its format
+func uploadedFile(r *http.Request) (io.ReadCloser, string, error) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		if format == "" {
+			format = formatFromMediaType(mediaType)
+		}
+		return r.Body, format, nil
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, "", err
+	}
+	if format == "" {
+		format = formatFromMediaType(header.Header.Get("Content-Type"))
+	}
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(header.Filename)) {
+		case ".csv":
+			format = models.ImportFormatCSV
+		case ".ndjson", ".jsonl":
+			format = models.ImportFormatNDJSON
+		}
+	}
+	return file, format, nil
+}
+
+func formatFromMediaType(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return models.ImportFormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/jsonlines":
+		return models.ImportFormatNDJSON
+	}
+	return ""
+}
+
+// handleUploadError maps errors of reading the upload to HTTP responses
+func (h *importHandlers) handleUploadError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		http.Error(w, "Import file is too large", http.StatusRequestEntityTooLarge)
+	case errors.Is(err, models.ErrInvalidImport):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, http.ErrMissingFile):
+		http.Error(w, "Form field file is required", http.StatusBadRequest)
+	default:
+		h.logger.Error("Error reading import file", err)
+		http.Error(w, "Invalid import file", http.StatusBadRequest)
+	}
+}
+
+func (h *importHandlers) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,221 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"musiclib/internal/models"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxFieldLength matches the size of the group, song and link columns
+const maxFieldLength = 255
+
+// maxLineSize bounds a single NDJSON line, lyrics included
+const maxLineSize = 1 << 20
+
+// csvColumns maps accepted CSV header names to row fields
+var csvColumns = map[string]string{
+	"group":        "group",
+	"group_name":   "group",
+	"song":         "song",
+	"title":        "song",
+	"text":         "text",
+	"lyrics":       "text",
+	"link":         "link",
+	"release_date": "releaseDate",
+	"releasedate":  "releaseDate",
+}
+
+// ndjsonRow is a single NDJSON line
+type ndjsonRow struct {
+	Group       string `json:"group"`
+	Song        string `json:"song"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+	ReleaseDate string `json:"releaseDate"`
+}
+
+// parseRows reads the uploaded file, rows that fail validation are kept as failed rows
+// so they show up in the job results
+func parseRows(r io.Reader, format string, maxRows int) ([]models.ImportRow, error) {
+	var (
+		rows []models.ImportRow
+		err  error
+	)
+	switch format {
+	case models.ImportFormatCSV:
+		rows, err = parseCSV(r, maxRows)
+	case models.ImportFormatNDJSON:
+		rows, err = parseNDJSON(r, maxRows)
+	case "":
+		return nil, fmt.Errorf("%w: unknown format, pass format=csv or format=ndjson", models.ErrInvalidImport)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", models.ErrInvalidImport, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows", models.ErrInvalidImport)
+	}
+
+	for i := range rows {
+		validateRow(&rows[i])
+	}
+	return rows, nil
+}
+
+func parseCSV(r io.Reader, maxRows int) ([]models.ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read CSV header: %v", models.ErrInvalidImport, err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))
+		if field, ok := csvColumns[name]; ok {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["group"]; !ok {
+		return nil, fmt.Errorf("%w: CSV header has no group column", models.ErrInvalidImport)
+	}
+	if _, ok := columns["song"]; !ok {
+		return nil, fmt.Errorf("%w: CSV header has no song column", models.ErrInvalidImport)
+	}
+
+	rows := make([]models.ImportRow, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", models.ErrInvalidImport, err)
+		}
+		if len(rows) == maxRows {
+			return nil, fmt.Errorf("%w: more than %d rows", models.ErrInvalidImport, maxRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return record[i]
+		}
+		rows = append(rows, models.ImportRow{
+			Line:        line,
+			Group:       field("group"),
+			Song:        field("song"),
+			Text:        field("text"),
+			Link:        field("link"),
+			ReleaseDate: field("releaseDate"),
+		})
+	}
+	return rows, nil
+}
+
+func parseNDJSON(r io.Reader, maxRows int) ([]models.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	rows := make([]models.ImportRow, 0)
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		if len(rows) == maxRows {
+			return nil, fmt.Errorf("%w: more than %d rows", models.ErrInvalidImport, maxRows)
+		}
+
+		var item ndjsonRow
+		if err := json.Unmarshal(data, &item); err != nil {
+			rows = append(rows, models.ImportRow{
+				Line:   line,
+				Status: models.ImportRowFailed,
+				Error:  "invalid JSON: " + err.Error(),
+			})
+			continue
+		}
+		rows = append(rows, models.ImportRow{
+			Line:        line,
+			Group:       item.Group,
+			Song:        item.Song,
+			Text:        item.Text,
+			Link:        item.Link,
+			ReleaseDate: item.ReleaseDate,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: line %d: %v", models.ErrInvalidImport, line+1, err)
+	}
+	return rows, nil
+}
+
+// validateRow trims the fields and marks the row as failed when it can't be imported
+func validateRow(row *models.ImportRow) {
+	if row.Status == models.ImportRowFailed {
+		return
+	}
+	row.Status = models.ImportRowPending
+
+	row.Group = strings.TrimSpace(row.Group)
+	row.Song = strings.TrimSpace(row.Song)
+	row.Link = strings.TrimSpace(row.Link)
+	row.ReleaseDate = strings.TrimSpace(row.ReleaseDate)
+
+	fail := func(message string) {
+		row.Status = models.ImportRowFailed
+		row.Error = message
+	}
+
+	switch {
+	case row.Group == "":
+		fail("group is required")
+	case row.Song == "":
+		fail("song is required")
+	case utf8.RuneCountInString(row.Group) > maxFieldLength:
+		fail("group is too long")
+	case utf8.RuneCountInString(row.Song) > maxFieldLength:
+		fail("song is too long")
+	case utf8.RuneCountInString(row.Link) > maxFieldLength:
+		fail("link is too long")
+	case utf8.RuneCountInString(row.ReleaseDate) > 32:
+		fail("release date is too long")
+	}
+	if row.Status == models.ImportRowFailed {
+		// Failed rows are stored as well, so they have to fit the columns
+		row.Group = truncate(row.Group, maxFieldLength)
+		row.Song = truncate(row.Song, maxFieldLength)
+		row.Link = truncate(row.Link, maxFieldLength)
+		row.ReleaseDate = truncate(row.ReleaseDate, 32)
+		return
+	}
+
+	if _, err := models.ParseReleaseDate(row.ReleaseDate); err != nil {
+		fail(err.Error())
+	}
+}
+
+func truncate(s string, n int) string {
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
@@ -0,0 +1,17 @@
+package http
+
+import (
+	"musiclib/internal/imports"
+
+	"github.com/gorilla/mux"
+)
+
+// RouteCreate names the upload route, rate limits are configured per route name
+const RouteCreate = "imports_create"
+
+// Map imports routes
+func MapImportRoutes(importsGroup *mux.Router, h imports.Handlers) {
+	importsGroup.HandleFunc("", h.Create).Methods("POST").Name(RouteCreate)
+	importsGroup.HandleFunc("/", h.Create).Methods("POST").Name(RouteCreate)
+	importsGroup.HandleFunc("/{id:[0-9]+}", h.GetByID).Methods("GET")
+}
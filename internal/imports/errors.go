@@ -0,0 +1,8 @@
+package imports
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("import job not found")
+	ErrNoPendingJobs = errors.New("no pending import jobs")
+)
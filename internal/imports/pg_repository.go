@@ -0,0 +1,19 @@
+package imports
+
+import (
+	"context"
+	"musiclib/internal/models"
+	"time"
+)
+
+// Repository interface
+type Repository interface {
+	Create(ctx context.Context, job *models.ImportJob, rows []models.ImportRow) (*models.ImportJob, error)
+	GetByID(ctx context.Context, id int) (*models.ImportJob, error)
+	GetRows(ctx context.Context, jobID int, status string, limit int, offset int) ([]models.ImportRow, error)
+	ClaimNext(ctx context.Context, staleBefore time.Time) (*models.ImportJob, error)
+	GetPendingRows(ctx context.Context, jobID int, limit int) ([]models.ImportRow, error)
+	SaveRowResult(ctx context.Context, row models.ImportRow) error
+	Finish(ctx context.Context, jobID int, status string, message string) error
+	Release(ctx context.Context, jobID int) error
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"musiclib/internal/imports"
+	"musiclib/internal/models"
+	"musiclib/pkg/logger"
+	"musiclib/pkg/metrics"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type importRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+func NewImportRepository(db *sqlx.DB, logger logger.Logger) *importRepository {
+	return &importRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create stores the job together with its rows, rows that failed validation are counted as processed
+func (r *importRepository) Create(ctx context.Context, job *models.ImportJob, rows []models.ImportRow) (*models.ImportJob, error) {
+	defer metrics.ObserveQuery("imports", "Create")()
+	r.logger.Debug("Starting Create in import repository", "format", job.Format, "rows", len(rows))
+
+	lines := make([]int64, len(rows))
+	groups := make([]string, len(rows))
+	songs := make([]string, len(rows))
+	releaseDates := make([]string, len(rows))
+	texts := make([]string, len(rows))
+	links := make([]string, len(rows))
+	statuses := make([]string, len(rows))
+	rowErrors := make([]string, len(rows))
+	failed := 0
+	for i, row := range rows {
+		lines[i] = int64(row.Line)
+		groups[i] = row.Group
+		songs[i] = row.Song
+		releaseDates[i] = row.ReleaseDate
+		texts[i] = row.Text
+		links[i] = row.Link
+		statuses[i] = row.Status
+		rowErrors[i] = row.Error
+		if row.Status == models.ImportRowFailed {
+			failed++
+		}
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var created models.ImportJob
+	err = tx.GetContext(ctx, &created, createImportJob, job.Format, job.DryRun, job.Actor, len(rows), failed)
+	if err != nil {
+		r.logger.Debug("Failed to create import job", "error", err)
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, insertImportRows,
+		created.ID,
+		pq.Array(lines),
+		pq.Array(groups),
+		pq.Array(songs),
+		pq.Array(releaseDates),
+		pq.Array(texts),
+		pq.Array(links),
+		pq.Array(statuses),
+		pq.Array(rowErrors),
+	)
+	if err != nil {
+		r.logger.Debug("Failed to insert import rows", "error", err)
+		return nil, fmt.Errorf("failed to insert import rows: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit import job: %w", err)
+	}
+
+	r.logger.Debug("Successfully created import job", "id", created.ID)
+	return &created, nil
+}
+
+func (r *importRepository) GetByID(ctx context.Context, id int) (*models.ImportJob, error) {
+	defer metrics.ObserveQuery("imports", "GetByID")()
+	r.logger.Debug("Starting GetByID in import repository", "id", id)
+
+	var job models.ImportJob
+	if err := r.db.GetContext(ctx, &job, getImportJob, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, imports.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get import job: %w", err)
+	}
+	return &job, nil
+}
+
+// GetRows returns a page of the job rows in file order, an empty status returns rows in any status
+func (r *importRepository) GetRows(ctx context.Context, jobID int, status string, limit int, offset int) ([]models.ImportRow, error) {
+	defer metrics.ObserveQuery("imports", "GetRows")()
+	r.logger.Debug("Starting GetRows in import repository",
+		"jobID", jobID,
+		"status", status,
+		"limit", limit,
+		"offset", offset,
+	)
+
+	rows := make([]models.ImportRow, 0)
+	if err := r.db.SelectContext(ctx, &rows, getImportRows, jobID, status, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to get import rows: %w", err)
+	}
+	return rows, nil
+}
+
+// ClaimNext marks the next job as running, ErrNoPendingJobs is returned when there is nothing to do
+func (r *importRepository) ClaimNext(ctx context.Context, staleBefore time.Time) (*models.ImportJob, error) {
+	defer metrics.ObserveQuery("imports", "ClaimNext")()
+	var job models.ImportJob
+	if err := r.db.GetContext(ctx, &job, claimImportJob, staleBefore); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, imports.ErrNoPendingJobs
+		}
+		return nil, fmt.Errorf("failed to claim import job: %w", err)
+	}
+
+	r.logger.Debug("Claimed import job", "id", job.ID)
+	return &job, nil
+}
+
+func (r *importRepository) GetPendingRows(ctx context.Context, jobID int, limit int) ([]models.ImportRow, error) {
+	defer metrics.ObserveQuery("imports", "GetPendingRows")()
+	rows := make([]models.ImportRow, 0)
+	if err := r.db.SelectContext(ctx, &rows, getPendingImportRows, jobID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get pending import rows: %w", err)
+	}
+	return rows, nil
+}
+
+// SaveRowResult stores the outcome of a row and adds it to the job counters
+func (r *importRepository) SaveRowResult(ctx context.Context, row models.ImportRow) error {
+	defer metrics.ObserveQuery("imports", "SaveRowResult")()
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, saveImportRow, row.JobID, row.Line, row.Status, row.Action, row.SongID, row.Error)
+	if err != nil {
+		return fmt.Errorf("failed to save import row: %w", err)
+	}
+	saved, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	// The row was already processed, its outcome is counted once
+	if saved == 0 {
+		return nil
+	}
+
+	var created, updated, skipped, failed int
+	switch {
+	case row.Status == models.ImportRowFailed:
+		failed = 1
+	case row.Action == models.ImportActionCreate:
+		created = 1
+	case row.Action == models.ImportActionUpdate:
+		updated = 1
+	default:
+		skipped = 1
+	}
+
+	if _, err := tx.ExecContext(ctx, addImportProgress, row.JobID, created, updated, skipped, failed); err != nil {
+		return fmt.Errorf("failed to update import progress: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit import row: %w", err)
+	}
+	return nil
+}
+
+func (r *importRepository) Finish(ctx context.Context, jobID int, status string, message string) error {
+	defer metrics.ObserveQuery("imports", "Finish")()
+	r.logger.Debug("Finishing import job", "id", jobID, "status", status)
+
+	if _, err := r.db.ExecContext(ctx, finishImportJob, jobID, status, message); err != nil {
+		return fmt.Errorf("failed to finish import job: %w", err)
+	}
+	return nil
+}
+
+// Release puts a running job back in the queue so another worker can continue it
+func (r *importRepository) Release(ctx context.Context, jobID int) error {
+	defer metrics.ObserveQuery("imports", "Release")()
+	if _, err := r.db.ExecContext(ctx, releaseImportJob, jobID); err != nil {
+		return fmt.Errorf("failed to release import job: %w", err)
+	}
+	return nil
+}
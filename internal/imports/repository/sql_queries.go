@@ -0,0 +1,70 @@
+package repository
+
+const importJobColumns = `id, status, format, dry_run, actor, total_rows, processed_rows, created_rows,
+    updated_rows, skipped_rows, failed_rows, error, created_at, updated_at, started_at, finished_at`
+
+const createImportJob = `
+    INSERT INTO import_jobs (format, dry_run, actor, total_rows, processed_rows, failed_rows)
+    VALUES ($1, $2, $3, $4, $5, $5)
+    RETURNING ` + importJobColumns
+
+const insertImportRows = `
+    INSERT INTO import_job_rows (job_id, line, group_name, song, release_date, text, link, status, error)
+    SELECT $1, u.line, u.group_name, u.song, u.release_date, u.text, u.link, u.status, u.error
+    FROM unnest($2::int[], $3::text[], $4::text[], $5::text[], $6::text[], $7::text[], $8::text[], $9::text[])
+        AS u(line, group_name, song, release_date, text, link, status, error)`
+
+const getImportJob = `SELECT ` + importJobColumns + ` FROM import_jobs WHERE id = $1`
+
+const importRowColumns = `job_id, line, group_name, song, release_date, text, link, status, action,
+    COALESCE(song_id, 0) AS song_id, error`
+
+const getImportRows = `
+    SELECT ` + importRowColumns + `
+    FROM import_job_rows
+    WHERE job_id = $1 AND ($2 = '' OR status = $2)
+    ORDER BY line
+    LIMIT $3 OFFSET $4`
+
+const getPendingImportRows = `
+    SELECT ` + importRowColumns + `
+    FROM import_job_rows
+    WHERE job_id = $1 AND status = 'pending'
+    ORDER BY line
+    LIMIT $2`
+
+// claimImportJob picks the oldest pending job, running jobs that stopped reporting progress
+// are taken over as well, SKIP LOCKED lets several workers claim jobs concurrently
+const claimImportJob = `
+    UPDATE import_jobs
+    SET status = 'running', started_at = COALESCE(started_at, NOW()), updated_at = NOW()
+    WHERE id = (
+        SELECT id FROM import_jobs
+        WHERE status = 'pending' OR (status = 'running' AND updated_at < $1)
+        ORDER BY id
+        LIMIT 1
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING ` + importJobColumns
+
+const saveImportRow = `
+    UPDATE import_job_rows
+    SET status = $3, action = $4, song_id = NULLIF($5, 0), error = $6
+    WHERE job_id = $1 AND line = $2 AND status = 'pending'`
+
+const addImportProgress = `
+    UPDATE import_jobs
+    SET processed_rows = processed_rows + 1,
+        created_rows = created_rows + $2,
+        updated_rows = updated_rows + $3,
+        skipped_rows = skipped_rows + $4,
+        failed_rows = failed_rows + $5,
+        updated_at = NOW()
+    WHERE id = $1`
+
+const finishImportJob = `
+    UPDATE import_jobs
+    SET status = $2, error = $3, finished_at = NOW(), updated_at = NOW()
+    WHERE id = $1`
+
+const releaseImportJob = `UPDATE import_jobs SET status = 'pending', updated_at = NOW() WHERE id = $1 AND status = 'running'`
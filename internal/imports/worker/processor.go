@@ -0,0 +1,283 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"musiclib/config"
+	"musiclib/internal/artist"
+	"musiclib/internal/imports"
+	"musiclib/internal/models"
+	"musiclib/internal/musicinfo"
+	"musiclib/internal/song"
+	"musiclib/pkg/audit"
+	"musiclib/pkg/logger"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	// staleJobTimeout is how long a running job may go without progress before another worker takes it over
+	staleJobTimeout = 5 * time.Minute
+	rowsBatchSize   = 100
+)
+
+// SourceImport marks changes made by bulk imports in the song history
+const SourceImport = "import"
+
+// Processor runs import jobs in the background, several processors can share the queue
+type Processor struct {
+	importRepo imports.Repository
+	songRepo   song.Repository
+	artistRepo artist.Repository
+	musicInfo  musicinfo.Provider
+	logger     logger.Logger
+	interval   time.Duration
+}
+
+// NewProcessor Processor constructor
+func NewProcessor(cfg config.ImportsConfig, importRepo imports.Repository, songRepo song.Repository, artistRepo artist.Repository, musicInfo musicinfo.Provider, logger logger.Logger) *Processor {
+	p := &Processor{
+		importRepo: importRepo,
+		songRepo:   songRepo,
+		artistRepo: artistRepo,
+		musicInfo:  musicInfo,
+		logger:     logger,
+		interval:   cfg.PollInterval,
+	}
+	if p.interval <= 0 {
+		p.interval = defaultPollInterval
+	}
+	return p
+}
+
+// Run processes queued jobs until ctx is canceled
+func (p *Processor) Run(ctx context.Context) {
+	p.logger.Infof("Import processor started, poll interval: %s", p.interval)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			p.logger.Info("Import processor stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain processes jobs one after another while there are any
+func (p *Processor) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := p.importRepo.ClaimNext(ctx, time.Now().Add(-staleJobTimeout))
+		if errors.Is(err, imports.ErrNoPendingJobs) {
+			return
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				p.logger.Errorf("Failed to claim import job: %v", err)
+			}
+			return
+		}
+		p.process(ctx, job)
+	}
+}
+
+func (p *Processor) process(ctx context.Context, job *models.ImportJob) {
+	p.logger.Infof("Processing import job %d, rows: %d, dry run: %t", job.ID, job.Total, job.DryRun)
+
+	// Song revisions written by the job are attributed to whoever uploaded it
+	ctx = audit.WithSource(audit.WithActor(ctx, job.Actor), SourceImport)
+
+	// In a dry run nothing is written, so repeated rows are tracked here
+	planned := make(map[string]bool)
+
+	for {
+		rows, err := p.importRepo.GetPendingRows(ctx, job.ID, rowsBatchSize)
+		if err != nil {
+			p.stop(ctx, job, err)
+			return
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		for _, row := range rows {
+			if ctx.Err() != nil {
+				p.stop(ctx, job, ctx.Err())
+				return
+			}
+
+			result := p.processRow(ctx, job, row, planned)
+			if err := p.importRepo.SaveRowResult(ctx, result); err != nil {
+				p.stop(ctx, job, err)
+				return
+			}
+		}
+	}
+
+	if err := p.importRepo.Finish(ctx, job.ID, models.ImportCompleted, ""); err != nil {
+		p.logger.Errorf("Failed to finish import job %d: %v", job.ID, err)
+		return
+	}
+	p.logger.Infof("Import job %d completed", job.ID)
+}
+
+// stop hands the job back to the queue on shutdown and marks it failed on other errors
+func (p *Processor) stop(ctx context.Context, job *models.ImportJob, err error) {
+	background := context.WithoutCancel(ctx)
+
+	if ctx.Err() != nil {
+		if err := p.importRepo.Release(background, job.ID); err != nil {
+			p.logger.Errorf("Failed to release import job %d: %v", job.ID, err)
+		}
+		return
+	}
+
+	p.logger.Errorf("Import job %d failed: %v", job.ID, err)
+	if err := p.importRepo.Finish(background, job.ID, models.ImportFailed, err.Error()); err != nil {
+		p.logger.Errorf("Failed to finish import job %d: %v", job.ID, err)
+	}
+}
+
+// processRow creates a new song, updates the fields given in the row of an existing one
+// or skips the row when the song already has them
+func (p *Processor) processRow(ctx context.Context, job *models.ImportJob, row models.ImportRow, planned map[string]bool) models.ImportRow {
+	row.Status = models.ImportRowDone
+
+	existing, err := p.songRepo.FindByTitle(ctx, row.Group, row.Song)
+	if err != nil && !errors.Is(err, song.ErrNotFound) {
+		return failRow(row, err)
+	}
+
+	if existing != nil {
+		row.SongID = existing.ID
+		patch, changed := rowPatch(row, existing)
+		if !changed {
+			row.Action = models.ImportActionSkip
+			return row
+		}
+
+		row.Action = models.ImportActionUpdate
+		if job.DryRun {
+			return row
+		}
+		if _, err := p.songRepo.Patch(ctx, existing.ID, patch); err != nil {
+			return failRow(row, err)
+		}
+		return row
+	}
+
+	if job.DryRun {
+		key := models.NormalizeArtistName(row.Group) + "\x00" + models.NormalizeArtistName(row.Song)
+		row.Action = models.ImportActionCreate
+		if planned[key] {
+			row.Action = models.ImportActionSkip
+		}
+		planned[key] = true
+		return row
+	}
+
+	created, err := p.createSong(ctx, row)
+	if err != nil {
+		return failRow(row, err)
+	}
+	row.Action = models.ImportActionCreate
+	row.SongID = created.ID
+	return row
+}
+
+// createSong stores a new song, details missing from the row are fetched from the music API
+func (p *Processor) createSong(ctx context.Context, row models.ImportRow) (*models.Song, error) {
+	releaseDate, err := models.ParseReleaseDate(row.ReleaseDate)
+	if err != nil {
+		return nil, err
+	}
+	newSong := &models.Song{
+		Song:        row.Song,
+		ReleaseDate: releaseDate,
+		Text:        normalizeText(row.Text),
+		Link:        row.Link,
+	}
+
+	if row.Text == "" || row.Link == "" || row.ReleaseDate == "" {
+		detail, err := p.musicInfo.GetSongDetail(ctx, row.Group, row.Song)
+		switch {
+		case err == nil:
+			fillMissing(newSong, detail)
+		case row.Text == "":
+			// Without lyrics there is nothing worth importing
+			return nil, err
+		case errors.Is(err, musicinfo.ErrNotFound), errors.Is(err, musicinfo.ErrInvalidRequest):
+			// Asking again won't help, the song is imported as failed and can be retried by hand
+			p.logger.Debug("Importing song without music API details", "line", row.Line, "error", err)
+			newSong.EnrichmentStatus = models.EnrichmentFailed
+			newSong.EnrichmentError = err.Error()
+		default:
+			// The enrichment workers retry the lookup and fill in only what the row didn't have
+			p.logger.Debug("Queueing imported song for enrichment", "line", row.Line, "error", err)
+			newSong.EnrichmentStatus = models.EnrichmentPending
+		}
+	}
+
+	songArtist, err := p.artistRepo.GetOrCreate(ctx, row.Group)
+	if err != nil {
+		return nil, err
+	}
+	newSong.ArtistID = songArtist.ID
+	newSong.Group = songArtist.Name
+
+	return p.songRepo.Create(ctx, newSong)
+}
+
+// fillMissing copies the details the row didn't have
+func fillMissing(s *models.Song, detail *models.SongDetail) {
+	if s.Text == "" {
+		s.Text = normalizeText(detail.Text)
+	}
+	if s.Link == "" {
+		s.Link = detail.Link
+	}
+	if s.ReleaseDate.IsZero() {
+		if releaseDate, err := models.ParseReleaseDate(detail.ReleaseDate); err == nil {
+			s.ReleaseDate = releaseDate
+		}
+	}
+}
+
+// rowPatch builds a patch of the fields given in the row that differ from the stored song
+func rowPatch(row models.ImportRow, existing *models.Song) (models.SongPatch, bool) {
+	var patch models.SongPatch
+	changed := false
+
+	if text := normalizeText(row.Text); text != "" && text != existing.Text {
+		patch.Text = models.Optional[string]{Set: true, Value: text}
+		changed = true
+	}
+	if row.Link != "" && row.Link != existing.Link {
+		patch.Link = models.Optional[string]{Set: true, Value: row.Link}
+		changed = true
+	}
+	if releaseDate, err := models.ParseReleaseDate(row.ReleaseDate); err == nil && !releaseDate.IsZero() &&
+		releaseDate.String() != existing.ReleaseDate.String() {
+		patch.ReleaseDate = models.Optional[models.ReleaseDate]{Set: true, Value: releaseDate}
+		changed = true
+	}
+	return patch, changed
+}
+
+// normalizeText turns the Windows line breaks common in CSV files into the plain ones lyrics are stored with
+func normalizeText(text string) string {
+	return strings.ReplaceAll(text, "\r\n", "\n")
+}
+
+func failRow(row models.ImportRow, err error) models.ImportRow {
+	row.Status = models.ImportRowFailed
+	row.Action = ""
+	row.Error = err.Error()
+	return row
+}
@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"musiclib/config"
+	"musiclib/internal/artist"
+	"musiclib/internal/models"
+	"musiclib/internal/musicinfo"
+	"musiclib/internal/song"
+	"musiclib/pkg/logger"
+	"testing"
+)
+
+type fakeMusicInfo struct {
+	detail *models.SongDetail
+	err    error
+}
+
+func (f fakeMusicInfo) GetSongDetail(ctx context.Context, group string, song string) (*models.SongDetail, error) {
+	return f.detail, f.err
+}
+
+type fakeArtistRepo struct {
+	artist.Repository
+}
+
+func (fakeArtistRepo) GetOrCreate(ctx context.Context, name string) (*models.Artist, error) {
+	return &models.Artist{ID: 1, Name: name}, nil
+}
+
+// fakeSongRepo keeps the song passed to Create, other methods panic through the nil interface
+type fakeSongRepo struct {
+	song.Repository
+	created *models.Song
+}
+
+func (r *fakeSongRepo) Create(ctx context.Context, s *models.Song) (*models.Song, error) {
+	r.created = s
+	s.ID = 1
+	return s, nil
+}
+
+func TestCreateSongEnrichmentStatus(t *testing.T) {
+	unavailable := fmt.Errorf("%w: timeout", musicinfo.ErrUnavailable)
+	detail := &models.SongDetail{ReleaseDate: "1965-09-13", Text: "Yesterday", Link: "https://example.com/yesterday"}
+
+	tests := []struct {
+		name       string
+		row        models.ImportRow
+		musicInfo  fakeMusicInfo
+		wantErr    error
+		wantStatus string
+		wantError  string
+	}{
+		{
+			name:       "complete row skips the lookup",
+			row:        models.ImportRow{Group: "The Beatles", Song: "Yesterday", ReleaseDate: "1965", Text: "Yesterday", Link: "https://example.com"},
+			musicInfo:  fakeMusicInfo{err: errors.New("must not be called")},
+			wantStatus: "",
+		},
+		{
+			name:       "details found",
+			row:        models.ImportRow{Group: "The Beatles", Song: "Yesterday"},
+			musicInfo:  fakeMusicInfo{detail: detail},
+			wantStatus: "",
+		},
+		{
+			name:      "lyrics missing and lookup failed",
+			row:       models.ImportRow{Group: "The Beatles", Song: "Yesterday"},
+			musicInfo: fakeMusicInfo{err: unavailable},
+			wantErr:   musicinfo.ErrUnavailable,
+		},
+		{
+			name:       "lookup unavailable",
+			row:        models.ImportRow{Group: "The Beatles", Song: "Yesterday", Text: "Yesterday"},
+			musicInfo:  fakeMusicInfo{err: unavailable},
+			wantStatus: models.EnrichmentPending,
+		},
+		{
+			name:       "song unknown to the music API",
+			row:        models.ImportRow{Group: "The Beatles", Song: "Yesterday", Text: "Yesterday"},
+			musicInfo:  fakeMusicInfo{err: musicinfo.ErrNotFound},
+			wantStatus: models.EnrichmentFailed,
+			wantError:  musicinfo.ErrNotFound.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songRepo := &fakeSongRepo{}
+			p := NewProcessor(config.ImportsConfig{}, nil, songRepo, fakeArtistRepo{}, tt.musicInfo, logger.NewApiLogger(&config.Config{}))
+
+			_, err := p.createSong(context.Background(), tt.row)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("createSong() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("createSong() error: %v", err)
+			}
+			if got := songRepo.created; got.EnrichmentStatus != tt.wantStatus || got.EnrichmentError != tt.wantError {
+				t.Errorf("created song enrichment = %q %q, want %q %q", got.EnrichmentStatus, got.EnrichmentError, tt.wantStatus, tt.wantError)
+			}
+		})
+	}
+}
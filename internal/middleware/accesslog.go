@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"musiclib/pkg/logger"
+	"musiclib/pkg/requestid"
+	"musiclib/pkg/tracing"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type routeKey struct{}
+
+// routeInfo is filled in by RecordRoute once the router has matched the request,
+// the middleware outside the router would not see the route otherwise
+type routeInfo struct {
+	template string
+}
+
+// withRouteInfo returns the route holder of r, adding one to its context when no outer middleware did
+func withRouteInfo(r *http.Request) (*routeInfo, *http.Request) {
+	if info, ok := r.Context().Value(routeKey{}).(*routeInfo); ok {
+		return info, r
+	}
+	info := &routeInfo{}
+	return info, r.WithContext(context.WithValue(r.Context(), routeKey{}, info))
+}
+
+// responseRecorder remembers the status and size of the response
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the flusher and deadlines of the connection
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// wroteHeader reports whether the response has started
+func (w *responseRecorder) wroteHeader() bool {
+	return w.status != 0
+}
+
+// statusCode is the status sent, handlers that write nothing send 200
+func (w *responseRecorder) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// record returns w as a responseRecorder, reusing the one of an outer middleware
+func record(w http.ResponseWriter) *responseRecorder {
+	if rec, ok := w.(*responseRecorder); ok {
+		return rec
+	}
+	return &responseRecorder{ResponseWriter: w}
+}
+
+// AccessLog writes one structured line per request once it is served
+func AccessLog(logger logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			info, r := withRouteInfo(r)
+			rec := record(w)
+
+			next.ServeHTTP(rec, r)
+
+			status := rec.statusCode()
+			fields := []interface{}{
+				"request_id", requestid.FromContext(r.Context()),
+				"method", r.Method,
+				"route", info.template,
+				"path", r.URL.Path,
+				"status", status,
+				"bytes", rec.bytes,
+				"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+				"remote_addr", r.RemoteAddr,
+			}
+			fields = append(fields, tracing.LogFields(r.Context())...)
+
+			if status >= http.StatusInternalServerError {
+				logger.Errorw("HTTP request", fields...)
+			} else {
+				logger.Infow("HTTP request", fields...)
+			}
+		})
+	}
+}
+
+// RecordRoute passes the matched route template to the access log, it is used on the router
+func RecordRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if info, ok := r.Context().Value(routeKey{}).(*routeInfo); ok {
+			if route := mux.CurrentRoute(r); route != nil {
+				info.template, _ = route.GetPathTemplate()
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
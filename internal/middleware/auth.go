@@ -0,0 +1,145 @@
+// Package middleware holds the HTTP middleware shared by the API routes.
+package middleware
+
+import (
+	"errors"
+	"musiclib/internal/apikey"
+	"musiclib/internal/auth"
+	"musiclib/internal/models"
+	"musiclib/pkg/audit"
+	"musiclib/pkg/logger"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Authorization schemes, users send access tokens and service clients API keys
+const (
+	bearerScheme = "Bearer"
+	apiKeyScheme = "ApiKey"
+)
+
+// apiKeyActorPrefix tells changes made with an API key from changes made by users
+const apiKeyActorPrefix = "apikey:"
+
+// Policy maps HTTP methods to the least role allowed to use them, methods missing from it need an admin
+type Policy map[string]string
+
+// AuthMiddleware checks access tokens, API keys and roles
+type AuthMiddleware struct {
+	tokens     *auth.Tokens
+	apiKeyRepo apikey.Repository
+	logger     logger.Logger
+}
+
+// NewAuthMiddleware Auth middleware constructor
+func NewAuthMiddleware(tokens *auth.Tokens, apiKeyRepo apikey.Repository, logger logger.Logger) *AuthMiddleware {
+	return &AuthMiddleware{tokens: tokens, apiKeyRepo: apiKeyRepo, logger: logger}
+}
+
+// Authenticate rejects requests without a valid access token or API key. The caller is stored
+// in the request context and recorded as the audit actor in place of any X-Actor header
+func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, credentials, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		credentials = strings.TrimSpace(credentials)
+
+		var principal models.Principal
+		var err error
+		switch {
+		case credentials == "":
+			unauthorized(w, "", "Authentication required")
+			return
+		case strings.EqualFold(scheme, bearerScheme):
+			principal, err = m.tokens.ParseAccessToken(credentials)
+		case strings.EqualFold(scheme, apiKeyScheme):
+			principal, err = m.authenticateKey(r, credentials)
+		default:
+			unauthorized(w, "", "Authentication required")
+			return
+		}
+
+		if err != nil {
+			if !errors.Is(err, auth.ErrInvalidToken) && !errors.Is(err, apikey.ErrInvalidKey) {
+				m.logger.Error("Error authenticating request", err)
+				http.Error(w, "Error authenticating request", http.StatusInternalServerError)
+				return
+			}
+			m.logger.Debug("Rejected credentials", "error", err, "scheme", scheme, "path", r.URL.Path)
+			unauthorized(w, ` error="invalid_token"`, err.Error())
+			return
+		}
+
+		ctx := auth.WithPrincipal(r.Context(), principal)
+		ctx = audit.WithActor(ctx, principal.Username)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authenticateKey looks the API key up, which also counts the request against it
+func (m *AuthMiddleware) authenticateKey(r *http.Request, key string) (models.Principal, error) {
+	k, err := m.apiKeyRepo.Authenticate(r.Context(), apikey.Hash(key))
+	if err != nil {
+		return models.Principal{}, err
+	}
+
+	return models.Principal{
+		APIKeyID: k.ID,
+		Username: apiKeyActorPrefix + k.Name,
+		Scopes:   k.Scopes,
+	}, nil
+}
+
+// unauthorized challenges the client with both schemes
+func unauthorized(w http.ResponseWriter, params string, message string) {
+	w.Header().Add("WWW-Authenticate", bearerScheme+params)
+	w.Header().Add("WWW-Authenticate", apiKeyScheme+params)
+	http.Error(w, message, http.StatusUnauthorized)
+}
+
+// Authorize lets a request through when the caller's role is allowed to use its method. API keys
+// need the scope of that role on resource instead, see models.ScopeFor. It must run after Authenticate
+func (m *AuthMiddleware) Authorize(policy Policy, resource string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			required, ok := policy[r.Method]
+			if !ok {
+				required = models.RoleAdmin
+			}
+
+			principal, ok := auth.PrincipalFrom(r.Context())
+			if !ok {
+				http.Error(w, "Authentication required", http.StatusUnauthorized)
+				return
+			}
+
+			if principal.APIKeyID != 0 {
+				scope := models.ScopeFor(resource, required)
+				if !principal.HasScope(scope) {
+					m.logger.Info("Access denied",
+						"user", principal.Username,
+						"scopes", principal.Scopes,
+						"required", scope,
+						"method", r.Method,
+						"path", r.URL.Path,
+					)
+					http.Error(w, "Insufficient scope, "+scope+" required", http.StatusForbidden)
+					return
+				}
+			} else if !principal.HasRole(required) {
+				m.logger.Info("Access denied",
+					"user", principal.Username,
+					"role", principal.Role,
+					"required", required,
+					"method", r.Method,
+					"path", r.URL.Path,
+				)
+				http.Error(w, "Insufficient role, "+required+" required", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"context"
+	"musiclib/config"
+	"musiclib/internal/apikey"
+	"musiclib/internal/auth"
+	"musiclib/internal/models"
+	"musiclib/pkg/audit"
+	"musiclib/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testAuthConfig = config.AuthConfig{
+	JWTSecret:       "0123456789abcdef0123456789abcdef",
+	Issuer:          "musiclib",
+	AccessTokenTTL:  time.Minute,
+	RefreshTokenTTL: time.Hour,
+}
+
+func newTestLogger() logger.Logger {
+	cfg := &config.Config{}
+	cfg.Logger.Level = "fatal"
+	l := logger.NewApiLogger(cfg)
+	l.InitLogger()
+	return l
+}
+
+func newTestTokens(t *testing.T, cfg config.AuthConfig) *auth.Tokens {
+	t.Helper()
+	tokens, err := auth.NewTokens(cfg)
+	if err != nil {
+		t.Fatalf("NewTokens: %v", err)
+	}
+	return tokens
+}
+
+func accessToken(t *testing.T, tokens *auth.Tokens, role string) string {
+	t.Helper()
+	token, err := tokens.IssueAccessToken(&models.User{ID: 7, Username: "dj_anna", Role: role})
+	if err != nil {
+		t.Fatalf("IssueAccessToken: %v", err)
+	}
+	return token
+}
+
+// actorHandler answers with the audit actor the middleware left in the context
+var actorHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(audit.Actor(r.Context())))
+})
+
+func TestAuthenticateBearer(t *testing.T) {
+	tokens := newTestTokens(t, testAuthConfig)
+	otherIssuer := testAuthConfig
+	otherIssuer.Issuer = "someone-else"
+	otherSecret := testAuthConfig
+	otherSecret.JWTSecret = "fedcba9876543210fedcba9876543210"
+
+	tests := []struct {
+		name          string
+		authorization string
+		want          int
+		wantBody      string
+	}{
+		{"valid token", "Bearer " + accessToken(t, tokens, models.RoleViewer), http.StatusOK, "dj_anna"},
+		{"scheme is case-insensitive", "bearer " + accessToken(t, tokens, models.RoleViewer), http.StatusOK, "dj_anna"},
+		{"no header", "", http.StatusUnauthorized, ""},
+		{"no credentials", "Bearer ", http.StatusUnauthorized, ""},
+		{"unknown scheme", "Basic ZGo6cGFzcw==", http.StatusUnauthorized, ""},
+		{"garbage token", "Bearer not-a-jwt", http.StatusUnauthorized, ""},
+		{"other issuer", "Bearer " + accessToken(t, newTestTokens(t, otherIssuer), models.RoleViewer), http.StatusUnauthorized, ""},
+		{"other secret", "Bearer " + accessToken(t, newTestTokens(t, otherSecret), models.RoleAdmin), http.StatusUnauthorized, ""},
+	}
+
+	m := NewAuthMiddleware(tokens, nil, newTestLogger())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/songs", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			req.Header.Set(audit.ActorHeader, "spoofed")
+			rec := httptest.NewRecorder()
+			m.Authenticate(actorHandler).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d %q, want %d", rec.Code, rec.Body.String(), tt.want)
+			}
+			if tt.want == http.StatusUnauthorized {
+				if got := rec.Header().Values("WWW-Authenticate"); len(got) != 2 {
+					t.Errorf("WWW-Authenticate = %v, want a Bearer and an ApiKey challenge", got)
+				}
+				return
+			}
+			if rec.Body.String() != tt.wantBody {
+				t.Errorf("audit actor = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestAuthorizeRoles(t *testing.T) {
+	tokens := newTestTokens(t, testAuthConfig)
+	policy := Policy{
+		http.MethodGet:  models.RoleViewer,
+		http.MethodPost: models.RoleEditor,
+	}
+
+	tests := []struct {
+		name   string
+		role   string
+		method string
+		policy Policy
+		want   int
+	}{
+		{"viewer reads", models.RoleViewer, http.MethodGet, policy, http.StatusOK},
+		{"viewer cannot add", models.RoleViewer, http.MethodPost, policy, http.StatusForbidden},
+		{"editor adds", models.RoleEditor, http.MethodPost, policy, http.StatusOK},
+		{"editor cannot delete", models.RoleEditor, http.MethodDelete, policy, http.StatusForbidden},
+		{"admin deletes", models.RoleAdmin, http.MethodDelete, policy, http.StatusOK},
+		{"closed registration refuses editors", models.RoleEditor, http.MethodPost, Policy{}, http.StatusForbidden},
+		{"closed registration lets admins in", models.RoleAdmin, http.MethodPost, Policy{}, http.StatusOK},
+	}
+
+	m := NewAuthMiddleware(tokens, nil, newTestLogger())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/songs", nil)
+			req.Header.Set("Authorization", "Bearer "+accessToken(t, tokens, tt.role))
+			rec := httptest.NewRecorder()
+			m.Authenticate(m.Authorize(tt.policy, models.ResourceSongs)(actorHandler)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("%s as %s = %d %q, want %d", tt.method, tt.role, rec.Code, rec.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizeWithoutAuthenticate(t *testing.T) {
+	m := NewAuthMiddleware(nil, nil, newTestLogger())
+	rec := httptest.NewRecorder()
+	m.Authorize(Policy{http.MethodGet: models.RoleViewer}, "")(actorHandler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401 without a principal", rec.Code)
+	}
+}
+
+// fakeAPIKeyRepo knows the keys by hash, methods a test does not need panic through the nil interface
+type fakeAPIKeyRepo struct {
+	apikey.Repository
+	keys map[string]*models.APIKey
+}
+
+func (r *fakeAPIKeyRepo) Authenticate(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	if k, ok := r.keys[keyHash]; ok {
+		return k, nil
+	}
+	return nil, apikey.ErrInvalidKey
+}
+
+func TestAuthorizeAPIKeyScopes(t *testing.T) {
+	repo := &fakeAPIKeyRepo{keys: map[string]*models.APIKey{
+		apikey.Hash("read-key"):  {ID: 1, Name: "reporting", Scopes: []string{models.ScopeSongsRead}},
+		apikey.Hash("write-key"): {ID: 2, Name: "importer", Scopes: []string{models.ScopeSongsRead, models.ScopeSongsWrite}},
+		apikey.Hash("admin-key"): {ID: 3, Name: "ops", Scopes: []string{models.ScopeAdmin}},
+	}}
+	policy := Policy{
+		http.MethodGet:    models.RoleViewer,
+		http.MethodPost:   models.RoleEditor,
+		http.MethodDelete: models.RoleAdmin,
+	}
+
+	tests := []struct {
+		name      string
+		key       string
+		method    string
+		resource  string
+		want      int
+		wantActor string
+	}{
+		{"read scope lists songs", "read-key", http.MethodGet, models.ResourceSongs, http.StatusOK, "apikey:reporting"},
+		{"read scope cannot add songs", "read-key", http.MethodPost, models.ResourceSongs, http.StatusForbidden, ""},
+		{"write scope adds songs", "write-key", http.MethodPost, models.ResourceSongs, http.StatusOK, "apikey:importer"},
+		{"write scope cannot delete songs", "write-key", http.MethodDelete, models.ResourceSongs, http.StatusForbidden, ""},
+		{"song scopes do not reach other resources", "write-key", http.MethodGet, "", http.StatusForbidden, ""},
+		{"admin scope deletes songs", "admin-key", http.MethodDelete, models.ResourceSongs, http.StatusOK, "apikey:ops"},
+		{"admin scope reaches other resources", "admin-key", http.MethodPost, "", http.StatusOK, "apikey:ops"},
+		{"unknown key", "guessed-key", http.MethodGet, models.ResourceSongs, http.StatusUnauthorized, ""},
+	}
+
+	m := NewAuthMiddleware(nil, repo, newTestLogger())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/songs", nil)
+			req.Header.Set("Authorization", "ApiKey "+tt.key)
+			rec := httptest.NewRecorder()
+			m.Authenticate(m.Authorize(policy, tt.resource)(actorHandler)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("%s with %s = %d %q, want %d", tt.method, tt.key, rec.Code, rec.Body.String(), tt.want)
+			}
+			if tt.wantActor != "" && rec.Body.String() != tt.wantActor {
+				t.Errorf("audit actor = %q, want %q", rec.Body.String(), tt.wantActor)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"musiclib/pkg/metrics"
+	"net/http"
+	"time"
+)
+
+// Metrics counts requests and their latency per route template and status
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		info, r := withRouteInfo(r)
+		rec := record(w)
+
+		defer func() {
+			metrics.ObserveRequest(r.Method, info.template, rec.statusCode(), time.Since(start))
+		}()
+
+		next.ServeHTTP(rec, r)
+	})
+}
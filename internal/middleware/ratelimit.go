@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"musiclib/config"
+	"musiclib/internal/auth"
+	"musiclib/internal/models"
+	"musiclib/pkg/logger"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/time/rate"
+)
+
+// defaultIdleTimeout applies when the config leaves idle_timeout unset
+const defaultIdleTimeout = 10 * time.Minute
+
+// defaultBucket holds the requests of routes without a limit of their own
+const defaultBucket = "default"
+
+// authFailuresBucket counts the failed authentications of an IP address
+const authFailuresBucket = "auth_failures"
+
+// bucket is the token bucket of one client on one route
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter gives every client a token bucket, keyed by user, API key or IP address
+type RateLimiter struct {
+	cfg    config.RateLimitConfig
+	logger logger.Logger
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewRateLimiter Rate limiter constructor
+func NewRateLimiter(cfg config.RateLimitConfig, logger logger.Logger) *RateLimiter {
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = defaultIdleTimeout
+	}
+	return &RateLimiter{
+		cfg:       cfg,
+		logger:    logger,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Limit takes a token from the client's bucket and answers 429 when it is empty. It keys requests
+// by the authenticated caller, so it must run after Authenticate on protected routes
+func (l *RateLimiter) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.cfg.Enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		name, limit := l.routeLimit(r)
+		if limit.Requests <= 0 || limit.Period <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		now := time.Now()
+		lim := l.limiter(name+"|"+l.clientKey(r), limit, now)
+		allowed := lim.AllowN(now, 1)
+		tokens := lim.TokensAt(now)
+
+		burst := float64(lim.Burst())
+		perSecond := float64(lim.Limit())
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(lim.Burst()))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(int(math.Max(0, math.Floor(tokens)))))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(int(math.Ceil((burst-tokens)/perSecond))))
+		w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d;burst=%d", limit.Requests, int(limit.Period.Seconds()), lim.Burst()))
+
+		if !allowed {
+			l.logger.Info("Rate limit exceeded", "client", l.clientKey(r), "route", name, "path", r.URL.Path)
+			tooManyRequests(w, lim, tokens)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// LimitAuthFailures charges every 401 to the IP address of the caller and answers 429 once its
+// auth_failures bucket is empty. It runs in front of Authenticate and the sign in routes, so guessed
+// credentials are turned away before they cost a database lookup
+func (l *RateLimiter) LimitAuthFailures(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := l.cfg.AuthFailures
+		if !l.cfg.Enabled || limit.Requests <= 0 || limit.Period <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ip := l.clientIP(r)
+		now := time.Now()
+		lim := l.limiter(authFailuresBucket+"|ip:"+ip, limit, now)
+		if tokens := lim.TokensAt(now); tokens < 1 {
+			l.logger.Info("Too many failed authentications", "ip", ip, "path", r.URL.Path)
+			tooManyRequests(w, lim, tokens)
+			return
+		}
+
+		rec := record(w)
+		next.ServeHTTP(rec, r)
+		if rec.statusCode() == http.StatusUnauthorized {
+			lim.AllowN(time.Now(), 1)
+		}
+	})
+}
+
+// tooManyRequests answers 429 with the time until lim has a token again
+func tooManyRequests(w http.ResponseWriter, lim *rate.Limiter, tokens float64) {
+	retryAfter := int(math.Ceil((1 - tokens) / float64(lim.Limit())))
+
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(models.RateLimitResponse{
+		Message:    fmt.Sprintf("Rate limit exceeded, retry in %d seconds", retryAfter),
+		RetryAfter: retryAfter,
+	})
+}
+
+// routeLimit returns the bucket name and limit of the matched route
+func (l *RateLimiter) routeLimit(r *http.Request) (string, config.RateLimit) {
+	if route := mux.CurrentRoute(r); route != nil {
+		if limit, ok := l.cfg.Routes[route.GetName()]; ok {
+			return route.GetName(), limit
+		}
+	}
+	return defaultBucket, l.cfg.Default
+}
+
+// clientKey identifies the caller, anonymous requests are told apart by IP address
+func (l *RateLimiter) clientKey(r *http.Request) string {
+	if principal, ok := auth.PrincipalFrom(r.Context()); ok {
+		return principal.Key()
+	}
+	return "ip:" + l.clientIP(r)
+}
+
+func (l *RateLimiter) clientIP(r *http.Request) string {
+	if l.cfg.TrustForwardedFor {
+		// The proxy appends the address it saw, earlier entries come from the client
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			parts := strings.Split(forwarded, ",")
+			if ip := strings.TrimSpace(parts[len(parts)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// limiter returns the bucket for key, creating it on first use. Idle buckets are swept on the way,
+// a dropped bucket comes back full, which is what it would have refilled to anyway
+func (l *RateLimiter) limiter(key string, limit config.RateLimit, now time.Time) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > l.cfg.IdleTimeout {
+		for k, b := range l.buckets {
+			if now.Sub(b.lastSeen) > l.cfg.IdleTimeout {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = limit.Requests
+		}
+		perSecond := rate.Limit(float64(limit.Requests) / limit.Period.Seconds())
+		b = &bucket{limiter: rate.NewLimiter(perSecond, burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+	return b.limiter
+}
@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"encoding/json"
+	"musiclib/config"
+	"musiclib/internal/auth"
+	"musiclib/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func newTestRateLimiter(cfg config.RateLimitConfig) *RateLimiter {
+	cfg.Enabled = true
+	return NewRateLimiter(cfg, newTestLogger())
+}
+
+// request sends a request from addr, signed in as userID when it is not zero
+func request(h http.Handler, method string, target string, addr string, userID int) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	req.RemoteAddr = addr
+	if userID != 0 {
+		req = req.WithContext(auth.WithPrincipal(req.Context(), models.Principal{UserID: userID, Role: models.RoleViewer}))
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLimit(t *testing.T) {
+	l := newTestRateLimiter(config.RateLimitConfig{
+		Default: config.RateLimit{Requests: 2, Period: time.Hour},
+	})
+	h := l.Limit(okHandler)
+
+	for i := 0; i < 2; i++ {
+		if rec := request(h, http.MethodGet, "/", "10.0.0.1:1234", 1); rec.Code != http.StatusOK {
+			t.Fatalf("request %d = %d, want 200", i+1, rec.Code)
+		}
+	}
+
+	rec := request(h, http.MethodGet, "/", "10.0.0.1:1234", 1)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("third request = %d, want 429", rec.Code)
+	}
+	if rec.Header().Get("RateLimit-Remaining") != "0" || rec.Header().Get("RateLimit-Limit") != "2" {
+		t.Errorf("RateLimit-Limit %q, RateLimit-Remaining %q, want 2 and 0",
+			rec.Header().Get("RateLimit-Limit"), rec.Header().Get("RateLimit-Remaining"))
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("429 without Retry-After")
+	}
+	var resp models.RateLimitResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp.RetryAfter <= 0 {
+		t.Errorf("429 body = %+v, %v, want a positive retryAfter", resp, err)
+	}
+
+	// Users are limited on their own, whatever address they come from
+	if rec := request(h, http.MethodGet, "/", "10.0.0.1:1234", 2); rec.Code != http.StatusOK {
+		t.Errorf("another user = %d, want 200", rec.Code)
+	}
+	if rec := request(h, http.MethodGet, "/", "10.0.0.1:1234", 0); rec.Code != http.StatusOK {
+		t.Errorf("anonymous client = %d, want 200", rec.Code)
+	}
+}
+
+func TestLimitRoutes(t *testing.T) {
+	l := newTestRateLimiter(config.RateLimitConfig{
+		Default: config.RateLimit{Requests: 100, Period: time.Hour},
+		Routes: map[string]config.RateLimit{
+			"songs_search": {Requests: 1, Period: time.Hour},
+		},
+	})
+
+	router := mux.NewRouter()
+	router.Use(l.Limit)
+	router.Handle("/songs/search", okHandler).Name("songs_search")
+	router.Handle("/songs", okHandler)
+
+	if rec := request(router, http.MethodGet, "/songs/search", "10.0.0.1:1234", 1); rec.Code != http.StatusOK {
+		t.Fatalf("first search = %d, want 200", rec.Code)
+	}
+	if rec := request(router, http.MethodGet, "/songs/search", "10.0.0.1:1234", 1); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("second search = %d, want 429", rec.Code)
+	}
+	rec := request(router, http.MethodGet, "/songs", "10.0.0.1:1234", 1)
+	if rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Limit") != "100" {
+		t.Errorf("list = %d with limit %q, want 200 from the default bucket", rec.Code, rec.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestLimitDisabled(t *testing.T) {
+	l := NewRateLimiter(config.RateLimitConfig{
+		Default: config.RateLimit{Requests: 1, Period: time.Hour},
+	}, newTestLogger())
+	h := l.Limit(okHandler)
+
+	for i := 0; i < 3; i++ {
+		if rec := request(h, http.MethodGet, "/", "10.0.0.1:1234", 1); rec.Code != http.StatusOK {
+			t.Fatalf("request %d = %d, want 200 while rate limiting is disabled", i+1, rec.Code)
+		}
+	}
+}
+
+func TestLimitAuthFailures(t *testing.T) {
+	l := newTestRateLimiter(config.RateLimitConfig{
+		AuthFailures: config.RateLimit{Requests: 2, Period: time.Hour},
+	})
+
+	calls := 0
+	status := http.StatusUnauthorized
+	h := l.LimitAuthFailures(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+	}))
+
+	// Successful requests are not charged
+	status = http.StatusOK
+	for i := 0; i < 3; i++ {
+		request(h, http.MethodPost, "/auth/login", "10.0.0.1:1234", 0)
+	}
+
+	status = http.StatusUnauthorized
+	for i := 0; i < 2; i++ {
+		if rec := request(h, http.MethodPost, "/auth/login", "10.0.0.1:1234", 0); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("failed attempt %d = %d, want 401", i+1, rec.Code)
+		}
+	}
+
+	calls = 0
+	rec := request(h, http.MethodPost, "/auth/login", "10.0.0.1:5678", 0)
+	if rec.Code != http.StatusTooManyRequests || calls != 0 {
+		t.Errorf("attempt after the bucket emptied = %d with %d handler calls, want 429 before the handler", rec.Code, calls)
+	}
+
+	status = http.StatusOK
+	if rec := request(h, http.MethodPost, "/auth/login", "10.0.0.2:1234", 0); rec.Code != http.StatusOK {
+		t.Errorf("another address = %d, want 200", rec.Code)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name      string
+		trust     bool
+		forwarded string
+		want      string
+	}{
+		{"remote address", false, "", "10.0.0.1"},
+		{"forwarded header ignored without a proxy", false, "203.0.113.7", "10.0.0.1"},
+		{"address the proxy saw", true, "198.51.100.1, 203.0.113.7", "203.0.113.7"},
+		{"no forwarded header behind a proxy", true, "", "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestRateLimiter(config.RateLimitConfig{TrustForwardedFor: tt.trust})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+
+			if got := l.clientIP(req); got != tt.want {
+				t.Errorf("clientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
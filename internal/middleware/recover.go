@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"encoding/json"
+	"musiclib/internal/models"
+	"musiclib/pkg/logger"
+	"musiclib/pkg/requestid"
+	"musiclib/pkg/tracing"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recover turns a panic in a handler into a JSON 500 and logs it with the stack.
+// A response that has already started cannot be replaced, so its connection is dropped
+func Recover(logger logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := record(w)
+
+			defer func() {
+				v := recover()
+				if v == nil || v == http.ErrAbortHandler {
+					if v != nil {
+						panic(v)
+					}
+					return
+				}
+
+				fields := []interface{}{
+					"request_id", requestid.FromContext(r.Context()),
+					"method", r.Method,
+					"path", r.URL.Path,
+					"panic", v,
+					"stack", string(debug.Stack()),
+				}
+				logger.Errorw("Panic while handling request", append(fields, tracing.LogFields(r.Context())...)...)
+
+				if rec.wroteHeader() {
+					panic(http.ErrAbortHandler)
+				}
+
+				rec.Header().Set("Content-Type", "application/json")
+				rec.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(rec).Encode(models.ErrorResponse{Message: "Internal server error"})
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
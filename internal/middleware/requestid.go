@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"musiclib/pkg/requestid"
+	"net/http"
+)
+
+// RequestID reuses the client's X-Request-ID when it is safe to, otherwise it assigns a new one.
+// The ID is echoed in the response and stored in the request context
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		w.Header().Set(requestid.Header, id)
+		next.ServeHTTP(w, r.WithContext(requestid.WithID(r.Context(), id)))
+	})
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"encoding/json"
+	"musiclib/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// slowHandler waits for the request deadline and fails the way a cancelled query does
+var slowHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	<-r.Context().Done()
+	http.Error(w, "Error getting songs", http.StatusInternalServerError)
+})
+
+// deadlineHandler reports whether the request got a deadline
+var deadlineHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.Context().Deadline(); ok {
+		w.Write([]byte("deadline"))
+	}
+})
+
+func TestTimeout(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Timeout(10*time.Millisecond)(slowHandler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", rec.Code)
+	}
+	var resp models.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp.Message != "Request timed out" {
+		t.Errorf("body = %q, want the timeout message alone", rec.Body.String())
+	}
+}
+
+func TestTimeoutKeepsErrorsBeforeDeadline(t *testing.T) {
+	failing := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Error getting songs", http.StatusInternalServerError)
+	})
+
+	rec := httptest.NewRecorder()
+	Timeout(time.Minute)(failing).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusInternalServerError || rec.Body.String() != "Error getting songs\n" {
+		t.Errorf("response = %d %q, want the handler's 500", rec.Code, rec.Body.String())
+	}
+}
+
+func TestTimeoutExemptRoutes(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(Timeout(time.Minute, "songs_export"))
+	router.Handle("/songs/export", deadlineHandler).Name("songs_export")
+	router.Handle("/songs", deadlineHandler)
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"/songs", "deadline"},
+		{"/songs/export", ""},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		if rec.Body.String() != tt.want {
+			t.Errorf("GET %s deadline = %q, want %q", tt.target, rec.Body.String(), tt.want)
+		}
+	}
+}
+
+func TestTimeoutDisabled(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Timeout(0)(deadlineHandler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Body.String() != "" {
+		t.Error("request got a deadline with the timeout disabled")
+	}
+}
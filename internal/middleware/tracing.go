@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"musiclib/pkg/requestid"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("musiclib/internal/middleware")
+
+// Tracing starts a server span for each request, continuing the trace of the caller when it sent a
+// traceparent header. The span is named after the route template once the router has matched it
+func Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, "HTTP "+r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+				attribute.String("request_id", requestid.FromContext(ctx)),
+			),
+		)
+		defer span.End()
+
+		info, r := withRouteInfo(r.WithContext(ctx))
+		rec := record(w)
+
+		next.ServeHTTP(rec, r)
+
+		if info.template != "" {
+			span.SetName(r.Method + " " + info.template)
+			span.SetAttributes(semconv.HTTPRoute(info.template))
+		}
+		status := rec.statusCode()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
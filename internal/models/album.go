@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Album types
+const (
+	AlbumTypeLP          = "LP"
+	AlbumTypeEP          = "EP"
+	AlbumTypeSingle      = "single"
+	AlbumTypeCompilation = "compilation"
+)
+
+type Album struct {
+	ID          int         `json:"id" db:"id" example:"1"`
+	Title       string      `json:"title" db:"title" example:"Help!"`
+	ArtistID    int         `json:"artistId" db:"artist_id" example:"1"`
+	Artist      string      `json:"artist" db:"artist_name" example:"Beatles"`
+	ReleaseDate ReleaseDate `json:"releaseDate" db:"release_date" swaggertype:"string" example:"1965-08-06"`
+	Type        string      `json:"type" db:"type" example:"LP"`
+	TracksCount int         `json:"tracksCount" db:"tracks_count" example:"14"`
+	CreatedAt   time.Time   `json:"createdAt" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time   `json:"updatedAt" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type AlbumRequest struct {
+	Title       string `json:"title" example:"Help!"`
+	ArtistID    int    `json:"artistId,omitempty" example:"1"`
+	Artist      string `json:"artist,omitempty" example:"Beatles"`
+	ReleaseDate string `json:"releaseDate,omitempty" example:"1965-08-06"`
+	Type        string `json:"type" example:"LP"`
+}
+
+type AlbumTrack struct {
+	DiscNumber  int    `json:"discNumber" db:"disc_number" example:"1"`
+	TrackNumber int    `json:"trackNumber" db:"track_number" example:"13"`
+	SongID      int    `json:"songId" db:"song_id" example:"1"`
+	Song        string `json:"song" db:"song" example:"Yesterday"`
+	Group       string `json:"group" db:"group_name" example:"Beatles"`
+	Link        string `json:"link" db:"link" example:"https://example.com/song"`
+}
+
+type AlbumTracklist struct {
+	Album  Album        `json:"album"`
+	Tracks []AlbumTrack `json:"tracks"`
+}
+
+// IsValidAlbumType reports whether t is one of the supported album types
+func IsValidAlbumType(t string) bool {
+	switch t {
+	case AlbumTypeLP, AlbumTypeEP, AlbumTypeSingle, AlbumTypeCompilation:
+		return true
+	}
+	return false
+}
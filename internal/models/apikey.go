@@ -0,0 +1,76 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// API key scopes, songs:read and songs:write only reach the songs while admin reaches everything
+const (
+	ScopeSongsRead  = "songs:read"
+	ScopeSongsWrite = "songs:write"
+	ScopeAdmin      = "admin"
+)
+
+// ResourceSongs names the songs routes, the only resource with scopes of its own
+const ResourceSongs = "songs"
+
+// resourceScopes maps the role a route needs to the scope an API key needs for it, per resource
+var resourceScopes = map[string]map[string]string{
+	ResourceSongs: {
+		RoleViewer: ScopeSongsRead,
+		RoleEditor: ScopeSongsWrite,
+	},
+}
+
+var validScopes = map[string]bool{
+	ScopeSongsRead:  true,
+	ScopeSongsWrite: true,
+	ScopeAdmin:      true,
+}
+
+// APIKey is a credential for service clients, the key itself is only returned when it is created or rotated
+type APIKey struct {
+	ID           int        `json:"id" db:"id" example:"1"`
+	Name         string     `json:"name" db:"name" example:"nightly-import"`
+	Prefix       string     `json:"prefix" db:"prefix" example:"mlk_3fJx9QzA"`
+	Scopes       []string   `json:"scopes" db:"-" example:"songs:read,songs:write"`
+	CreatedBy    *int       `json:"createdBy,omitempty" db:"created_by" example:"1"`
+	CreatedAt    time.Time  `json:"createdAt" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty" db:"expires_at" example:"2025-01-01T00:00:00Z"`
+	RotatedAt    *time.Time `json:"rotatedAt,omitempty" db:"rotated_at"`
+	RevokedAt    *time.Time `json:"revokedAt,omitempty" db:"revoked_at"`
+	LastUsedAt   *time.Time `json:"lastUsedAt,omitempty" db:"last_used_at" example:"2024-01-02T03:04:05Z"`
+	RequestCount int64      `json:"requestCount" db:"request_count" example:"1532"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" example:"nightly-import"`
+	Scopes    []string   `json:"scopes" example:"songs:read,songs:write"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" example:"2025-01-01T00:00:00Z"`
+}
+
+// APIKeySecret carries the plain key, it cannot be retrieved again later
+type APIKeySecret struct {
+	APIKey
+	Key string `json:"key" example:"mlk_3fJx9QzA..."`
+}
+
+// IsValidScope reports whether scope is one of the supported API key scopes
+func IsValidScope(scope string) bool {
+	return validScopes[scope]
+}
+
+// ScopeFor returns the scope an API key needs where users need role on resource. Admin access
+// and resources without scopes of their own need the admin scope
+func ScopeFor(resource string, role string) string {
+	if scope, ok := resourceScopes[resource][role]; ok {
+		return scope
+	}
+	return ScopeAdmin
+}
+
+// NormalizeAPIKeyName returns the key used to tell API keys apart, names are case-insensitive
+func NormalizeAPIKeyName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
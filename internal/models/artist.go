@@ -0,0 +1,42 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+type Artist struct {
+	ID          int       `json:"id" db:"id" example:"1"`
+	Name        string    `json:"name" db:"name" example:"Beatles"`
+	Description string    `json:"description" db:"description" example:"English rock band formed in Liverpool"`
+	SongsCount  int       `json:"songsCount" db:"songs_count" example:"12"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updatedAt" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type ArtistRequest struct {
+	Name        string `json:"name" example:"Beatles"`
+	Description string `json:"description" example:"English rock band formed in Liverpool"`
+}
+
+type MergeArtistsRequest struct {
+	SourceID int `json:"sourceId" example:"2"`
+	TargetID int `json:"targetId" example:"1"`
+}
+
+// MergeConflictResponse is returned when both artists have songs with the same title
+type MergeConflictResponse struct {
+	Message string   `json:"message" example:"both artists have songs with the same title"`
+	Titles  []string `json:"titles" example:"Yesterday"`
+}
+
+// CleanArtistName trims the name and collapses inner whitespace
+func CleanArtistName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// NormalizeArtistName returns the key used to tell artists apart,
+// so "Muse", "muse" and "MUSE " resolve to the same artist
+func NormalizeArtistName(name string) string {
+	return strings.ToLower(CleanArtistName(name))
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SongCursor points right after the last song of a page in keyset pagination.
+// Clients get it as an opaque string and must not rely on its contents.
+type SongCursor struct {
+	SortBy    string `json:"s"`
+	SortOrder string `json:"o"`
+	Value     string `json:"v,omitempty"`
+	ID        int    `json:"i"`
+}
+
+type SongPage struct {
+	Songs      []Song `json:"songs"`
+	NextCursor string `json:"next_cursor,omitempty" example:"eyJzIjoiaWQiLCJvIjoiYXNjIiwiaSI6MTB9"`
+}
+
+// NewSongCursor builds the cursor continuing after song in the given ordering
+func NewSongCursor(song Song, sortBy string, sortOrder string) SongCursor {
+	return SongCursor{
+		SortBy:    sortBy,
+		SortOrder: sortOrder,
+		Value:     song.SortKey(sortBy),
+		ID:        song.ID,
+	}
+}
+
+// Encode returns the opaque representation of the cursor
+func (c SongCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeSongCursor parses a cursor produced by Encode
+func DecodeSongCursor(s string) (*SongCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c SongCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if !IsValidSongSortField(c.SortBy) || (c.SortOrder != "asc" && c.SortOrder != "desc") || c.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	// The repository casts release dates in SQL, a tampered value must not reach the query
+	if c.SortBy == "release_date" {
+		d, err := ParseReleaseDate(c.Value)
+		if err != nil || d.IsZero() {
+			return nil, ErrInvalidCursor
+		}
+		c.Value = d.Time.Format(time.DateOnly)
+	}
+
+	return &c, nil
+}
+
+// SortKey returns the value of the sort field the songs list is ordered by
+func (s Song) SortKey(field string) string {
+	switch field {
+	case "group_name":
+		return s.Group
+	case "song":
+		return s.Song
+	case "release_date":
+		if s.ReleaseDate.IsZero() {
+			return "0001-01-01"
+		}
+		return s.ReleaseDate.Time.Format(time.DateOnly)
+	default:
+		return strconv.Itoa(s.ID)
+	}
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestNewSongCursor(t *testing.T) {
+	releaseDate, err := ParseReleaseDate("1965-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+	song := Song{ID: 42, Group: "The Beatles", Song: "Yesterday", ReleaseDate: releaseDate}
+
+	tests := []struct {
+		sortBy string
+		song   Song
+		value  string
+	}{
+		{"id", song, "42"},
+		{"group_name", song, "The Beatles"},
+		{"song", song, "Yesterday"},
+		{"release_date", song, "1965-08-01"},
+		{"release_date", Song{ID: 42}, "0001-01-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy+"/"+tt.value, func(t *testing.T) {
+			c := NewSongCursor(tt.song, tt.sortBy, "desc")
+			want := SongCursor{SortBy: tt.sortBy, SortOrder: "desc", Value: tt.value, ID: 42}
+			if c != want {
+				t.Errorf("NewSongCursor() = %+v, want %+v", c, want)
+			}
+		})
+	}
+}
+
+func TestSongCursorRoundTrip(t *testing.T) {
+	for _, sortBy := range SongSortFields {
+		for _, sortOrder := range []string{"asc", "desc"} {
+			t.Run(sortBy+"/"+sortOrder, func(t *testing.T) {
+				c := NewSongCursor(Song{ID: 7, Group: "Кино", Song: "Группа крови"}, sortBy, sortOrder)
+
+				got, err := DecodeSongCursor(c.Encode())
+				if err != nil {
+					t.Fatalf("DecodeSongCursor() error: %v", err)
+				}
+				if *got != c {
+					t.Errorf("DecodeSongCursor(Encode()) = %+v, want %+v", *got, c)
+				}
+			})
+		}
+	}
+}
+
+func TestDecodeSongCursorInvalid(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "%%%"},
+		{"not json", encode("id:10")},
+		{"unknown sort field", encode(`{"s":"text","o":"asc","i":10}`)},
+		{"unknown sort order", encode(`{"s":"id","o":"up","i":10}`)},
+		{"missing id", encode(`{"s":"id","o":"asc"}`)},
+		{"negative id", encode(`{"s":"id","o":"asc","i":-1}`)},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"s":"id","o":"asc","i":1}`))},
+		{"missing release date", encode(`{"s":"release_date","o":"asc","i":1}`)},
+		{"invalid release date", encode(`{"s":"release_date","o":"asc","v":"1965-13-01","i":1}`)},
+		{"injected release date", encode(`{"s":"release_date","o":"asc","v":"1965'); --","i":1}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeSongCursor(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeSongCursor(%q) error = %v, want ErrInvalidCursor", tt.cursor, err)
+			}
+		})
+	}
+}
+
+func TestDecodeSongCursorNormalizesReleaseDate(t *testing.T) {
+	c := SongCursor{SortBy: "release_date", SortOrder: "asc", Value: "13.9.1965", ID: 1}
+
+	got, err := DecodeSongCursor(c.Encode())
+	if err != nil {
+		t.Fatalf("DecodeSongCursor() error: %v", err)
+	}
+	if got.Value != "1965-09-13" {
+		t.Errorf("DecodeSongCursor() value = %q, want %q", got.Value, "1965-09-13")
+	}
+}
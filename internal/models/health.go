@@ -0,0 +1,28 @@
+package models
+
+// Health statuses of the service and of a single dependency
+const (
+	HealthOK           = "ok"
+	HealthReady        = "ready"
+	HealthDegraded     = "degraded"
+	HealthNotReady     = "not_ready"
+	HealthShuttingDown = "shutting_down"
+
+	HealthUp   = "up"
+	HealthDown = "down"
+)
+
+type HealthReport struct {
+	// Status is ok for the liveness probe. For readiness it is ready, degraded when only optional dependencies are down, not_ready or shutting_down
+	Status string        `json:"status" example:"ready"`
+	Checks []HealthCheck `json:"checks,omitempty"`
+}
+
+type HealthCheck struct {
+	Name   string `json:"name" example:"database"`
+	Status string `json:"status" example:"up"`
+	// Critical dependencies make the service not ready when they are down
+	Critical  bool    `json:"critical" example:"true"`
+	LatencyMs float64 `json:"latencyMs" example:"1.25"`
+	Error     string  `json:"error,omitempty" example:"database is at migration 13, expected 14"`
+}
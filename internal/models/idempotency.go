@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// IdempotencyRecord remembers the outcome of a request sent with an Idempotency-Key header,
+// each caller has keys of its own
+type IdempotencyRecord struct {
+	Principal   string    `db:"principal"`
+	Key         string    `db:"key"`
+	RequestHash string    `db:"request_hash"`
+	SongID      int       `db:"song_id"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// Completed reports whether the request that reserved the key has created its song
+func (r IdempotencyRecord) Completed() bool {
+	return r.SongID != 0
+}
+
+// SongConflictResponse is returned when the song already exists
+type SongConflictResponse struct {
+	Message string `json:"message" example:"song with this title already exists for the artist"`
+	ID      int    `json:"id" example:"1"`
+}
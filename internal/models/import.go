@@ -0,0 +1,78 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidImport is returned when an uploaded import file can't be parsed
+var ErrInvalidImport = errors.New("invalid import file")
+
+// Import formats
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+)
+
+// Import job statuses
+const (
+	ImportPending   = "pending"
+	ImportRunning   = "running"
+	ImportCompleted = "completed"
+	ImportFailed    = "failed"
+)
+
+// Import row statuses
+const (
+	ImportRowPending = "pending"
+	ImportRowDone    = "done"
+	ImportRowFailed  = "failed"
+)
+
+// Import row actions, in a dry run they tell what would have happened
+const (
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+	ImportActionSkip   = "skip"
+)
+
+// ImportJob is a bulk import of songs processed in the background
+type ImportJob struct {
+	ID         int        `json:"id" db:"id" example:"1"`
+	Status     string     `json:"status" db:"status" example:"running"`
+	Format     string     `json:"format" db:"format" example:"csv"`
+	DryRun     bool       `json:"dryRun" db:"dry_run" example:"false"`
+	Actor      string     `json:"actor" db:"actor" example:"editor@example.com"`
+	Total      int        `json:"total" db:"total_rows" example:"250"`
+	Processed  int        `json:"processed" db:"processed_rows" example:"120"`
+	Created    int        `json:"created" db:"created_rows" example:"100"`
+	Updated    int        `json:"updated" db:"updated_rows" example:"5"`
+	Skipped    int        `json:"skipped" db:"skipped_rows" example:"10"`
+	Failed     int        `json:"failed" db:"failed_rows" example:"5"`
+	Error      string     `json:"error,omitempty" db:"error"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updatedAt" db:"updated_at"`
+	StartedAt  *time.Time `json:"startedAt,omitempty" db:"started_at"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty" db:"finished_at"`
+}
+
+// ImportRow is a single line of an import file with its outcome
+type ImportRow struct {
+	JobID       int    `json:"-" db:"job_id"`
+	Line        int    `json:"line" db:"line" example:"2"`
+	Group       string `json:"group" db:"group_name" example:"Muse"`
+	Song        string `json:"song" db:"song" example:"Supermassive Black Hole"`
+	ReleaseDate string `json:"releaseDate,omitempty" db:"release_date" example:"2006-07-16"`
+	Text        string `json:"text,omitempty" db:"text"`
+	Link        string `json:"link,omitempty" db:"link"`
+	Status      string `json:"status" db:"status" example:"done"`
+	Action      string `json:"action,omitempty" db:"action" example:"create"`
+	SongID      int    `json:"songId,omitempty" db:"song_id" example:"42"`
+	Error       string `json:"error,omitempty" db:"error"`
+}
+
+// ImportJobDetail is an import job with a page of its rows
+type ImportJobDetail struct {
+	ImportJob
+	Rows []ImportRow `json:"rows"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// Lyrics pagination units
+const (
+	TextUnitLine   = "line"
+	TextUnitStanza = "stanza"
+)
+
+type Stanza struct {
+	Position int    `json:"index" db:"position" example:"0"`
+	Type     string `json:"type,omitempty" db:"type" example:"chorus"`
+	Text     string `json:"text" db:"text" example:"Yesterday all my troubles seemed so far away"`
+}
+
+// SongTextItem is a line or a whole stanza of the lyrics, depending on the unit
+type SongTextItem struct {
+	Stanza int    `json:"stanza" example:"0"`
+	Type   string `json:"type,omitempty" example:"chorus"`
+	Text   string `json:"text" example:"Yesterday all my troubles seemed so far away"`
+}
+
+type SongTextResponse struct {
+	Unit    string         `json:"unit" example:"line"`
+	Total   int            `json:"total" example:"24"`
+	Verses  []string       `json:"verses"`
+	Items   []SongTextItem `json:"items"`
+	HasMore bool           `json:"has_more" example:"true"`
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidAlbumPosition is returned when a song's album, disc and track numbers don't fit together
+var ErrInvalidAlbumPosition = errors.New("invalid album position")
+
+// ErrSongTitleRequired is returned when a patch would leave the song without a title
+var ErrSongTitleRequired = errors.New("song title is required")
+
+// Optional is a JSON field that tells a missing value apart from an explicit null
+type Optional[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// UnmarshalJSON is only called for fields present in the document
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// SongPatch is a JSON Merge Patch (RFC 7396) of a song: missing fields are left alone,
+// null clears the field
+type SongPatch struct {
+	Group       Optional[string]      `json:"group"`
+	Song        Optional[string]      `json:"song"`
+	ReleaseDate Optional[ReleaseDate] `json:"releaseDate"`
+	Text        Optional[string]      `json:"text"`
+	Link        Optional[string]      `json:"link"`
+	AlbumID     Optional[int]         `json:"albumId"`
+	DiscNumber  Optional[int]         `json:"discNumber"`
+	TrackNumber Optional[int]         `json:"trackNumber"`
+
+	// ArtistID is the artist resolved from Group, zero clears the artist
+	ArtistID int `json:"-"`
+}
+
+// Validate rejects patches clearing the fields every song must have
+func (p SongPatch) Validate() error {
+	if p.Song.Set && (p.Song.Null || strings.TrimSpace(p.Song.Value) == "") {
+		return ErrSongTitleRequired
+	}
+	return nil
+}
+
+// TouchesAlbum reports whether the patch changes the song's album position
+func (p SongPatch) TouchesAlbum() bool {
+	return p.AlbumID.Set || p.DiscNumber.Set || p.TrackNumber.Set
+}
+
+// ApplyTo merges the patch into the song
+func (p SongPatch) ApplyTo(song *Song) {
+	if p.Group.Set {
+		song.ArtistID = p.ArtistID
+		song.Group = p.Group.Value
+	}
+	if p.Song.Set {
+		song.Song = p.Song.Value
+	}
+	if p.ReleaseDate.Set {
+		song.ReleaseDate = p.ReleaseDate.Value
+	}
+	if p.Text.Set {
+		song.Text = p.Text.Value
+	}
+	if p.Link.Set {
+		song.Link = p.Link.Value
+	}
+	if p.AlbumID.Set {
+		song.AlbumID = p.AlbumID.Value
+	}
+	if p.DiscNumber.Set {
+		song.DiscNumber = p.DiscNumber.Value
+	}
+	if p.TrackNumber.Set {
+		song.TrackNumber = p.TrackNumber.Value
+	}
+}
+
+// NormalizeAlbumPosition validates the album position, songs without an album drop their
+// disc and track numbers and the disc defaults to the first one
+func (s *Song) NormalizeAlbumPosition() error {
+	if s.AlbumID < 0 || s.DiscNumber < 0 || s.TrackNumber < 0 {
+		return fmt.Errorf("%w: album, disc and track numbers must be positive", ErrInvalidAlbumPosition)
+	}
+	if s.AlbumID == 0 {
+		s.DiscNumber = 0
+		s.TrackNumber = 0
+		return nil
+	}
+	if s.TrackNumber == 0 {
+		return fmt.Errorf("%w: track number is required when attaching a song to an album", ErrInvalidAlbumPosition)
+	}
+	if s.DiscNumber == 0 {
+		s.DiscNumber = 1
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestOptionalUnmarshalJSON(t *testing.T) {
+	type doc struct {
+		Text Optional[string] `json:"text"`
+	}
+
+	tests := []struct {
+		name string
+		json string
+		want Optional[string]
+	}{
+		{"missing", `{}`, Optional[string]{}},
+		{"null", `{"text": null}`, Optional[string]{Set: true, Null: true}},
+		{"value", `{"text": "la la"}`, Optional[string]{Set: true, Value: "la la"}},
+		{"empty value", `{"text": ""}`, Optional[string]{Set: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d doc
+			if err := json.Unmarshal([]byte(tt.json), &d); err != nil {
+				t.Fatalf("Unmarshal(%s) error: %v", tt.json, err)
+			}
+			if d.Text != tt.want {
+				t.Errorf("Unmarshal(%s) = %+v, want %+v", tt.json, d.Text, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptionalUnmarshalJSONWrongType(t *testing.T) {
+	var p SongPatch
+	if err := json.Unmarshal([]byte(`{"albumId": "one"}`), &p); err == nil {
+		t.Error("Unmarshal of a string into albumId succeeded, want an error")
+	}
+	if err := json.Unmarshal([]byte(`{"releaseDate": "someday"}`), &p); !errors.Is(err, ErrInvalidReleaseDate) {
+		t.Errorf("Unmarshal of an invalid releaseDate error = %v, want ErrInvalidReleaseDate", err)
+	}
+}
+
+func TestSongPatchApplyTo(t *testing.T) {
+	releaseDate, err := ParseReleaseDate("1965-08-06")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newDate, err := ParseReleaseDate("1966-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := Song{
+		ArtistID:    1,
+		Group:       "The Beatles",
+		Song:        "Yesterday",
+		ReleaseDate: releaseDate,
+		Text:        "Yesterday",
+		Link:        "https://example.com/yesterday",
+		AlbumID:     2,
+		DiscNumber:  1,
+		TrackNumber: 13,
+	}
+
+	tests := []struct {
+		name     string
+		json     string
+		artistID int
+		want     func(s *Song)
+	}{
+		{
+			name: "empty patch changes nothing",
+			json: `{}`,
+			want: func(s *Song) {},
+		},
+		{
+			name: "value replaces the field",
+			json: `{"song": "Help!", "releaseDate": "1966-08"}`,
+			want: func(s *Song) {
+				s.Song = "Help!"
+				s.ReleaseDate = newDate
+			},
+		},
+		{
+			name: "null clears the field",
+			json: `{"text": null, "link": null, "releaseDate": null}`,
+			want: func(s *Song) {
+				s.Text = ""
+				s.Link = ""
+				s.ReleaseDate = ReleaseDate{}
+			},
+		},
+		{
+			name:     "group takes the resolved artist",
+			json:     `{"group": "Wings"}`,
+			artistID: 7,
+			want: func(s *Song) {
+				s.ArtistID = 7
+				s.Group = "Wings"
+			},
+		},
+		{
+			name: "null album clears the album",
+			json: `{"albumId": null}`,
+			want: func(s *Song) {
+				s.AlbumID = 0
+			},
+		},
+		{
+			name: "track only moves the track",
+			json: `{"trackNumber": 2}`,
+			want: func(s *Song) {
+				s.TrackNumber = 2
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch SongPatch
+			if err := json.Unmarshal([]byte(tt.json), &patch); err != nil {
+				t.Fatalf("Unmarshal(%s) error: %v", tt.json, err)
+			}
+			patch.ArtistID = tt.artistID
+
+			got := original
+			patch.ApplyTo(&got)
+
+			want := original
+			tt.want(&want)
+			if got != want {
+				t.Errorf("ApplyTo(%s) = %+v, want %+v", tt.json, got, want)
+			}
+		})
+	}
+}
+
+func TestSongPatchValidate(t *testing.T) {
+	tests := []struct {
+		json    string
+		wantErr error
+	}{
+		{`{}`, nil},
+		{`{"song": "Help!"}`, nil},
+		{`{"text": null, "link": ""}`, nil},
+		{`{"song": null}`, ErrSongTitleRequired},
+		{`{"song": ""}`, ErrSongTitleRequired},
+		{`{"song": " \t "}`, ErrSongTitleRequired},
+	}
+
+	for _, tt := range tests {
+		var patch SongPatch
+		if err := json.Unmarshal([]byte(tt.json), &patch); err != nil {
+			t.Fatalf("Unmarshal(%s) error: %v", tt.json, err)
+		}
+		if err := patch.Validate(); !errors.Is(err, tt.wantErr) {
+			t.Errorf("Validate(%s) error = %v, want %v", tt.json, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSongPatchTouchesAlbum(t *testing.T) {
+	tests := []struct {
+		json string
+		want bool
+	}{
+		{`{}`, false},
+		{`{"song": "Help!"}`, false},
+		{`{"albumId": null}`, true},
+		{`{"discNumber": 2}`, true},
+		{`{"trackNumber": 1}`, true},
+	}
+
+	for _, tt := range tests {
+		var patch SongPatch
+		if err := json.Unmarshal([]byte(tt.json), &patch); err != nil {
+			t.Fatalf("Unmarshal(%s) error: %v", tt.json, err)
+		}
+		if got := patch.TouchesAlbum(); got != tt.want {
+			t.Errorf("TouchesAlbum(%s) = %v, want %v", tt.json, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeAlbumPosition(t *testing.T) {
+	tests := []struct {
+		name    string
+		song    Song
+		want    Song
+		wantErr bool
+	}{
+		{"no album drops the position", Song{DiscNumber: 2, TrackNumber: 3}, Song{}, false},
+		{"disc defaults to the first", Song{AlbumID: 1, TrackNumber: 3}, Song{AlbumID: 1, DiscNumber: 1, TrackNumber: 3}, false},
+		{"disc is kept", Song{AlbumID: 1, DiscNumber: 2, TrackNumber: 3}, Song{AlbumID: 1, DiscNumber: 2, TrackNumber: 3}, false},
+		{"album needs a track", Song{AlbumID: 1}, Song{}, true},
+		{"negative numbers", Song{AlbumID: 1, TrackNumber: -1}, Song{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.song
+			err := got.NormalizeAlbumPosition()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAlbumPosition) {
+					t.Errorf("NormalizeAlbumPosition() error = %v, want ErrInvalidAlbumPosition", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeAlbumPosition() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeAlbumPosition() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
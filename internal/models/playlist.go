@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+type Playlist struct {
+	ID           int       `json:"id" db:"id" example:"1"`
+	Name         string    `json:"name" db:"name" example:"Friday show"`
+	Description  string    `json:"description" db:"description" example:"Opening set"`
+	EntriesCount int       `json:"entriesCount" db:"entries_count" example:"12"`
+	CreatedAt    time.Time `json:"createdAt" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt    time.Time `json:"updatedAt" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type PlaylistRequest struct {
+	Name        string `json:"name" example:"Friday show"`
+	Description string `json:"description,omitempty" example:"Opening set"`
+}
+
+// PlaylistEntry is a song at a 1-based position, the same song may appear several times.
+// Songs moved to the trash stay in the playlist with deletedAt set
+type PlaylistEntry struct {
+	ID       int64     `json:"id" db:"id" example:"1"`
+	Position int       `json:"position" db:"position" example:"1"`
+	AddedAt  time.Time `json:"addedAt" db:"added_at" example:"2024-01-01T00:00:00Z"`
+	Song     Song      `json:"song" db:"song"`
+}
+
+type PlaylistDetail struct {
+	Playlist
+	Entries []PlaylistEntry `json:"entries"`
+}
+
+// AddPlaylistEntryRequest inserts the song before the entry at position, it is appended when position is omitted
+type AddPlaylistEntryRequest struct {
+	SongID   int `json:"songId" example:"1"`
+	Position int `json:"position,omitempty" example:"3"`
+}
+
+type MovePlaylistEntryRequest struct {
+	Position int `json:"position" example:"1"`
+}
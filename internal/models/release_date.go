@@ -0,0 +1,160 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidReleaseDate = errors.New("invalid release date")
+
+// DatePrecision tells which parts of a release date are known
+type DatePrecision string
+
+const (
+	PrecisionDay   DatePrecision = "day"
+	PrecisionMonth DatePrecision = "month"
+	PrecisionYear  DatePrecision = "year"
+)
+
+// releaseDateLayouts lists the accepted input formats, from the music API and from clients
+var releaseDateLayouts = []struct {
+	layout    string
+	precision DatePrecision
+}{
+	{"2006-1-2", PrecisionDay},
+	{"2.1.2006", PrecisionDay},
+	{"2/1/2006", PrecisionDay},
+	{"2006/1/2", PrecisionDay},
+	{"2006.1.2", PrecisionDay},
+	{time.RFC3339, PrecisionDay},
+	{"2006-01-02T15:04:05", PrecisionDay},
+	{"January 2, 2006", PrecisionDay},
+	{"Jan 2, 2006", PrecisionDay},
+	{"2 January 2006", PrecisionDay},
+	{"2 Jan 2006", PrecisionDay},
+	{"2006-1", PrecisionMonth},
+	{"1.2006", PrecisionMonth},
+	{"1/2006", PrecisionMonth},
+	{"January 2006", PrecisionMonth},
+	{"Jan 2006", PrecisionMonth},
+	{"2006", PrecisionYear},
+}
+
+// ReleaseDate is a calendar date that may be known only up to the month or the year.
+// It is rendered as ISO-8601 with the matching precision: 2006-07-16, 2006-07 or 2006.
+type ReleaseDate struct {
+	Time      time.Time
+	Precision DatePrecision
+}
+
+// ParseReleaseDate parses any of the supported formats, an empty string gives a zero date
+func ParseReleaseDate(s string) (ReleaseDate, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ReleaseDate{}, nil
+	}
+
+	for _, l := range releaseDateLayouts {
+		t, err := time.Parse(l.layout, s)
+		if err != nil {
+			continue
+		}
+		year, month, day := t.Date()
+		return ReleaseDate{
+			Time:      time.Date(year, month, day, 0, 0, 0, 0, time.UTC),
+			Precision: l.precision,
+		}, nil
+	}
+
+	return ReleaseDate{}, fmt.Errorf("%w: %q", ErrInvalidReleaseDate, s)
+}
+
+func (d ReleaseDate) IsZero() bool {
+	return d.Precision == ""
+}
+
+func (d ReleaseDate) String() string {
+	switch d.Precision {
+	case PrecisionDay:
+		return d.Time.Format(time.DateOnly)
+	case PrecisionMonth:
+		return d.Time.Format("2006-01")
+	case PrecisionYear:
+		return d.Time.Format("2006")
+	}
+	return ""
+}
+
+// Start returns the first day the date may refer to
+func (d ReleaseDate) Start() time.Time {
+	return d.Time
+}
+
+// End returns the last day the date may refer to
+func (d ReleaseDate) End() time.Time {
+	switch d.Precision {
+	case PrecisionMonth:
+		return d.Time.AddDate(0, 1, -1)
+	case PrecisionYear:
+		return d.Time.AddDate(1, 0, -1)
+	}
+	return d.Time
+}
+
+func (d ReleaseDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *ReleaseDate) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = ReleaseDate{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w: expected a string", ErrInvalidReleaseDate)
+	}
+
+	parsed, err := ParseReleaseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Scan reads the ISO representation produced by the format_release_date SQL function
+func (d *ReleaseDate) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = ReleaseDate{}
+		return nil
+	case []byte:
+		return d.scanString(string(v))
+	case string:
+		return d.scanString(v)
+	}
+	return fmt.Errorf("cannot scan %T into ReleaseDate", src)
+}
+
+func (d *ReleaseDate) scanString(s string) error {
+	parsed, err := ParseReleaseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value stores the first day of the date, the precision is kept in its own column
+func (d ReleaseDate) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.Time.Format(time.DateOnly), nil
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseReleaseDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      string
+		precision DatePrecision
+	}{
+		{"iso day", "2006-07-16", "2006-07-16", PrecisionDay},
+		{"iso day without padding", "2006-7-6", "2006-07-06", PrecisionDay},
+		{"dotted day", "16.07.2006", "2006-07-16", PrecisionDay},
+		{"slashed day", "16/07/2006", "2006-07-16", PrecisionDay},
+		{"year first slashed day", "2006/07/16", "2006-07-16", PrecisionDay},
+		{"year first dotted day", "2006.07.16", "2006-07-16", PrecisionDay},
+		{"rfc3339 drops the time", "2006-07-16T23:30:00+03:00", "2006-07-16", PrecisionDay},
+		{"timestamp without zone", "2006-07-16T10:00:00", "2006-07-16", PrecisionDay},
+		{"long english day", "July 16, 2006", "2006-07-16", PrecisionDay},
+		{"short english day", "Jul 16, 2006", "2006-07-16", PrecisionDay},
+		{"day first english", "16 July 2006", "2006-07-16", PrecisionDay},
+		{"day first short english", "16 Jul 2006", "2006-07-16", PrecisionDay},
+		{"iso month", "2006-07", "2006-07", PrecisionMonth},
+		{"dotted month", "07.2006", "2006-07", PrecisionMonth},
+		{"slashed month", "7/2006", "2006-07", PrecisionMonth},
+		{"english month", "July 2006", "2006-07", PrecisionMonth},
+		{"short english month", "Jul 2006", "2006-07", PrecisionMonth},
+		{"year", "2006", "2006", PrecisionYear},
+		{"surrounding spaces", "  2006-07-16 ", "2006-07-16", PrecisionDay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReleaseDate(tt.input)
+			if err != nil {
+				t.Fatalf("ParseReleaseDate(%q) error: %v", tt.input, err)
+			}
+			if got.String() != tt.want || got.Precision != tt.precision {
+				t.Errorf("ParseReleaseDate(%q) = %s (%s), want %s (%s)", tt.input, got, got.Precision, tt.want, tt.precision)
+			}
+			if got.Time.Location() != time.UTC || got.Time.Hour() != 0 {
+				t.Errorf("ParseReleaseDate(%q) time = %v, want midnight UTC", tt.input, got.Time)
+			}
+		})
+	}
+}
+
+func TestParseReleaseDateEmpty(t *testing.T) {
+	for _, input := range []string{"", "   "} {
+		got, err := ParseReleaseDate(input)
+		if err != nil {
+			t.Fatalf("ParseReleaseDate(%q) error: %v", input, err)
+		}
+		if !got.IsZero() {
+			t.Errorf("ParseReleaseDate(%q) = %s, want a zero date", input, got)
+		}
+	}
+}
+
+func TestParseReleaseDateInvalid(t *testing.T) {
+	for _, input := range []string{"yesterday", "2006-13-01", "2006-02-30", "32.01.2006", "06", "2006-07-16 extra"} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := ParseReleaseDate(input); !errors.Is(err, ErrInvalidReleaseDate) {
+				t.Errorf("ParseReleaseDate(%q) error = %v, want ErrInvalidReleaseDate", input, err)
+			}
+		})
+	}
+}
+
+func TestReleaseDateRange(t *testing.T) {
+	tests := []struct {
+		input      string
+		start, end string
+	}{
+		{"2006-07-16", "2006-07-16", "2006-07-16"},
+		{"2006-02", "2006-02-01", "2006-02-28"},
+		{"2004-02", "2004-02-01", "2004-02-29"},
+		{"2006", "2006-01-01", "2006-12-31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := ParseReleaseDate(tt.input)
+			if err != nil {
+				t.Fatalf("ParseReleaseDate(%q) error: %v", tt.input, err)
+			}
+			if got := d.Start().Format(time.DateOnly); got != tt.start {
+				t.Errorf("Start() = %s, want %s", got, tt.start)
+			}
+			if got := d.End().Format(time.DateOnly); got != tt.end {
+				t.Errorf("End() = %s, want %s", got, tt.end)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"musiclib/pkg/textdiff"
+	"time"
+)
+
+// Revision actions
+const (
+	RevisionCreate     = "create"
+	RevisionUpdate     = "update"
+	RevisionDelete     = "delete"
+	RevisionRestore    = "restore"
+	RevisionHardDelete = "hard_delete"
+	RevisionRollback   = "rollback"
+	RevisionPurge      = "purge"
+)
+
+// SongRevision is an immutable snapshot of a song taken after each change
+type SongRevision struct {
+	SongID    int       `json:"songId" db:"song_id" example:"1"`
+	Revision  int       `json:"revision" db:"revision" example:"3"`
+	Action    string    `json:"action" db:"action" example:"update"`
+	Actor     string    `json:"actor" db:"actor" example:"editor@example.com"`
+	Source    string    `json:"source" db:"source" example:"api"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	Snapshot  Song      `json:"snapshot" db:"-"`
+}
+
+// FieldChange is a song field that differs between two revisions
+type FieldChange struct {
+	Field string      `json:"field" example:"song"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// SongRevisionDiff describes the changes between two revisions of a song
+type SongRevisionDiff struct {
+	SongID int             `json:"songId" example:"1"`
+	From   int             `json:"from" example:"2"`
+	To     int             `json:"to" example:"3"`
+	Fields []FieldChange   `json:"fields"`
+	Lyrics []textdiff.Line `json:"lyrics,omitempty"`
+}
+
+// DiffSongs compares two snapshots field by field, changed lyrics are diffed line by line as well
+func DiffSongs(from, to Song) ([]FieldChange, []textdiff.Line) {
+	fields := make([]FieldChange, 0)
+	compare := func(name string, a, b interface{}) {
+		if a != b {
+			fields = append(fields, FieldChange{Field: name, From: a, To: b})
+		}
+	}
+
+	compare("artistId", from.ArtistID, to.ArtistID)
+	compare("group", from.Group, to.Group)
+	compare("song", from.Song, to.Song)
+	compare("releaseDate", from.ReleaseDate.String(), to.ReleaseDate.String())
+	compare("text", from.Text, to.Text)
+	compare("link", from.Link, to.Link)
+	compare("albumId", from.AlbumID, to.AlbumID)
+	compare("discNumber", from.DiscNumber, to.DiscNumber)
+	compare("trackNumber", from.TrackNumber, to.TrackNumber)
+	compare("deleted", from.DeletedAt != nil, to.DeletedAt != nil)
+	compare("enrichmentStatus", from.EnrichmentStatus, to.EnrichmentStatus)
+
+	if from.Text == to.Text {
+		return fields, nil
+	}
+	return fields, textdiff.Lines(from.Text, to.Text)
+}
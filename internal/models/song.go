@@ -1,12 +1,87 @@
 package models
 
+import "time"
+
 type Song struct {
-	ID          int    `json:"id" db:"id" example:"1"`
-	Group       string `json:"group" db:"group_name" example:"Beatles"`
-	Song        string `json:"song" db:"song" example:"Yesterday"`
-	ReleaseDate string `json:"releaseDate" db:"release_date" example:"1965-09-13"`
-	Text        string `json:"text" db:"text" example:"Yesterday all my troubles seemed so far away..."`
-	Link        string `json:"link" db:"link" example:"https://example.com/song"`
+	ID          int         `json:"id" db:"id" example:"1"`
+	ArtistID    int         `json:"artistId" db:"artist_id" example:"1"`
+	Group       string      `json:"group" db:"group_name" example:"Beatles"`
+	Song        string      `json:"song" db:"song" example:"Yesterday"`
+	ReleaseDate ReleaseDate `json:"releaseDate" db:"release_date" swaggertype:"string" example:"1965-09-13"`
+	Text        string      `json:"text" db:"text" example:"Yesterday all my troubles seemed so far away..."`
+	Link        string      `json:"link" db:"link" example:"https://example.com/song"`
+	AlbumID     int         `json:"albumId,omitempty" db:"album_id" example:"1"`
+	DiscNumber  int         `json:"discNumber,omitempty" db:"disc_number" example:"1"`
+	TrackNumber int         `json:"trackNumber,omitempty" db:"track_number" example:"13"`
+	DeletedAt   *time.Time  `json:"deletedAt,omitempty" db:"deleted_at"`
+	// EnrichmentStatus tells whether the details from the music API are still pending, were filled in or failed
+	EnrichmentStatus string `json:"enrichmentStatus" db:"enrichment_status" example:"enriched"`
+	EnrichmentError  string `json:"enrichmentError,omitempty" db:"enrichment_error" example:""`
+}
+
+// Enrichment statuses of a song
+const (
+	EnrichmentPending  = "pending"
+	EnrichmentEnriched = "enriched"
+	EnrichmentFailed   = "failed"
+)
+
+// EnrichmentJob is a song waiting for its music API details, Attempts counts this one
+type EnrichmentJob struct {
+	SongID   int    `db:"song_id"`
+	Group    string `db:"group_name"`
+	Song     string `db:"song"`
+	Attempts int    `db:"attempts"`
+}
+
+// SongSortFields lists the fields the songs list can be sorted by
+var SongSortFields = []string{"id", "group_name", "song", "release_date"}
+
+// SongFilter narrows down the songs list, empty fields are ignored
+type SongFilter struct {
+	Group         string
+	GroupContains string
+	SongContains  string
+	ReleasedFrom  ReleaseDate
+	ReleasedTo    ReleaseDate
+	HasLink       *bool
+	TextContains  string
+	// Tags, TagsAny and TagsNone hold normalized tag names: songs must carry all of Tags,
+	// at least one of TagsAny and none of TagsNone
+	Tags     []string
+	TagsAny  []string
+	TagsNone []string
+}
+
+type SongListQuery struct {
+	Filter    SongFilter
+	SortBy    string
+	SortOrder string
+	Limit     int
+	Offset    int
+	// After switches to keyset pagination, Offset is ignored when it is set
+	After *SongCursor
+}
+
+// IsValidSongSortField reports whether the songs list can be sorted by field
+func IsValidSongSortField(field string) bool {
+	for _, f := range SongSortFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+type SongSearchResult struct {
+	ID          int     `json:"id" db:"id" example:"1"`
+	ArtistID    int     `json:"artistId" db:"artist_id" example:"1"`
+	Group       string  `json:"group" db:"group_name" example:"Beatles"`
+	Song        string  `json:"song" db:"song" example:"Yesterday"`
+	Rank        float64 `json:"rank" db:"rank" example:"0.42"`
+	Snippet     string  `json:"snippet" db:"snippet" example:"<b>Yesterday</b> all my troubles seemed so far away"`
+	VerseIndex  *int    `json:"verseIndex,omitempty" db:"verse_index" example:"0"`
+	StanzaIndex *int    `json:"stanzaIndex,omitempty" db:"stanza_index" example:"0"`
 }
 
 type AddSongRequest struct {
@@ -14,12 +89,16 @@ type AddSongRequest struct {
 	Song  string `json:"song" example:"Yesterday"`
 }
 
+// UpdateSongRequest documents the song fields accepted by PUT and PATCH, see SongPatch for the merge semantics
 type UpdateSongRequest struct {
 	Group       string `json:"group,omitempty" example:"Beatles"`
 	Song        string `json:"song,omitempty" example:"Yesterday"`
 	ReleaseDate string `json:"releaseDate,omitempty" example:"1965-09-13"`
 	Text        string `json:"text,omitempty" example:"Yesterday all my troubles seemed so far away..."`
 	Link        string `json:"link,omitempty" example:"https://example.com/song"`
+	AlbumID     int    `json:"albumId,omitempty" example:"1"`
+	DiscNumber  int    `json:"discNumber,omitempty" example:"1"`
+	TrackNumber int    `json:"trackNumber,omitempty" example:"13"`
 }
 
 type SongDetail struct {
@@ -31,3 +110,9 @@ type SongDetail struct {
 type ErrorResponse struct {
 	Message string `json:"message" example:"error message"`
 }
+
+type RateLimitResponse struct {
+	Message string `json:"message" example:"Rate limit exceeded, retry in 12 seconds"`
+	// RetryAfter is the number of seconds to wait, the same as the Retry-After header
+	RetryAfter int `json:"retryAfter" example:"12"`
+}
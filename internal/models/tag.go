@@ -0,0 +1,45 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Tag kinds
+const (
+	TagKindGenre = "genre"
+	TagKindLabel = "label"
+)
+
+// MaxTagNameLength matches the size of the tags name column
+const MaxTagNameLength = 100
+
+type Tag struct {
+	ID         int       `json:"id" db:"id" example:"1"`
+	Name       string    `json:"name" db:"name" example:"needs review"`
+	Kind       string    `json:"kind" db:"kind" example:"label"`
+	SongsCount int       `json:"songsCount" db:"songs_count" example:"12"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt  time.Time `json:"updatedAt" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type TagRequest struct {
+	Name string `json:"name" example:"needs review"`
+	Kind string `json:"kind" example:"label"`
+}
+
+// IsValidTagKind reports whether kind is one of the supported tag kinds
+func IsValidTagKind(kind string) bool {
+	return kind == TagKindGenre || kind == TagKindLabel
+}
+
+// CleanTagName trims the name and collapses inner whitespace
+func CleanTagName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// NormalizeTagName returns the key used to tell tags apart,
+// so "Rock", "rock" and " ROCK" resolve to the same tag
+func NormalizeTagName(name string) string {
+	return strings.ToLower(CleanTagName(name))
+}
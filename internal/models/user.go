@@ -0,0 +1,94 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// User roles, each role can do everything the roles before it can
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+var roleRanks = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+type User struct {
+	ID           int       `json:"id" db:"id" example:"1"`
+	Username     string    `json:"username" db:"username" example:"dj_anna"`
+	Role         string    `json:"role" db:"role" example:"editor"`
+	PasswordHash string    `json:"-" db:"password_hash"`
+	CreatedAt    time.Time `json:"createdAt" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt    time.Time `json:"updatedAt" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// Principal is the authenticated caller of a request, either a user with a role or an API key with scopes
+type Principal struct {
+	UserID   int
+	APIKeyID int
+	Username string
+	Role     string
+	Scopes   []string
+}
+
+type CredentialsRequest struct {
+	Username string `json:"username" example:"dj_anna"`
+	Password string `json:"password" example:"correct horse battery staple"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" example:"2Jc0kWb9yQ..."`
+}
+
+type UpdateRoleRequest struct {
+	Role string `json:"role" example:"editor"`
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"accessToken" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string `json:"refreshToken" example:"2Jc0kWb9yQ..."`
+	TokenType    string `json:"tokenType" example:"Bearer"`
+	// ExpiresIn is the lifetime of the access token in seconds
+	ExpiresIn int `json:"expiresIn" example:"900"`
+}
+
+// IsValidRole reports whether role is one of the supported roles
+func IsValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// HasRole reports whether the principal's role includes the required one
+func (p Principal) HasRole(required string) bool {
+	rank, ok := roleRanks[p.Role]
+	return ok && rank >= roleRanks[required]
+}
+
+// Key identifies the caller, API keys and users are told apart by a prefix
+func (p Principal) Key() string {
+	if p.APIKeyID != 0 {
+		return "apikey:" + strconv.Itoa(p.APIKeyID)
+	}
+	return "user:" + strconv.Itoa(p.UserID)
+}
+
+// HasScope reports whether the principal was granted scope, the admin scope includes every other one
+func (p Principal) HasScope(scope string) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeUsername returns the key used to tell users apart, usernames are case-insensitive
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
@@ -0,0 +1,258 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"musiclib/config"
+	"musiclib/internal/models"
+	"musiclib/internal/musicinfo"
+	"musiclib/pkg/breaker"
+	"musiclib/pkg/logger"
+	"musiclib/pkg/metrics"
+	"musiclib/pkg/requestid"
+	"musiclib/pkg/tracing"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const defaultAuthHeader = "Authorization"
+
+var tracer = otel.Tracer("musiclib/internal/musicinfo/client")
+
+// Music info client, fetches song details from the configured music API over HTTP
+type musicInfoClient struct {
+	cfg        config.MusicApiConfig
+	httpClient *http.Client
+	breaker    *breaker.Breaker
+	logger     logger.Logger
+}
+
+// NewMusicInfoClient music API client constructor, one client should be shared so that
+// all callers see the same circuit breaker
+func NewMusicInfoClient(cfg config.MusicApiConfig, logger logger.Logger) *musicInfoClient {
+	return &musicInfoClient{
+		cfg:        cfg,
+		httpClient: &http.Client{},
+		breaker:    breaker.New(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		logger:     logger,
+	}
+}
+
+// GetSongDetail asks the music API for the release date, text and link of a song
+func (c *musicInfoClient) GetSongDetail(ctx context.Context, group string, song string) (*models.SongDetail, error) {
+	ctx, span := tracer.Start(ctx, "music_api GET", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	start := time.Now()
+	detail, err := c.getSongDetail(ctx, group, song)
+	metrics.ObserveMusicAPI(outcome(err), time.Since(start))
+
+	span.SetAttributes(attribute.String("music_api.outcome", outcome(err)))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return detail, err
+}
+
+func (c *musicInfoClient) getSongDetail(ctx context.Context, group string, song string) (*models.SongDetail, error) {
+	apiURL := fmt.Sprintf("%s?group=%s&song=%s",
+		c.cfg.URL,
+		url.QueryEscape(strings.ToLower(group)),
+		url.QueryEscape(strings.ToLower(song)),
+	)
+
+	if err := c.breaker.Allow(); err != nil {
+		return nil, musicinfo.ErrCircuitOpen
+	}
+
+	var (
+		detail *models.SongDetail
+		err    error
+	)
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		detail, retry, err = c.fetch(ctx, apiURL)
+		if !retry || attempt >= c.cfg.MaxRetries || ctx.Err() != nil {
+			break
+		}
+
+		delay := c.backoff(attempt)
+		if !c.fits(ctx, delay) {
+			c.logger.Debug("Not retrying music API request, the caller's deadline is too close",
+				"error", err,
+				"attempt", attempt+1,
+				"request_id", requestid.FromContext(ctx),
+			)
+			break
+		}
+		fields := []interface{}{
+			"error", err,
+			"attempt", attempt + 1,
+			"delay", delay,
+			"request_id", requestid.FromContext(ctx),
+		}
+		c.logger.Warnw("Retrying music API request", append(fields, tracing.LogFields(ctx)...)...)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	// Only failures of the API count against it, not rejected input or a caller that gave up or ran out of time
+	switch {
+	case ctx.Err() != nil:
+		c.breaker.Release()
+	case errors.Is(err, musicinfo.ErrUnavailable):
+		c.breaker.Failure()
+	default:
+		c.breaker.Success()
+	}
+	return detail, err
+}
+
+// fetch makes a single attempt, retry tells whether the failure may be transient. The attempt gets
+// the configured timeout, or less when the caller's deadline comes first
+func (c *musicInfoClient) fetch(ctx context.Context, apiURL string) (*models.SongDetail, bool, error) {
+	if c.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", musicinfo.ErrUnavailable, err)
+	}
+	if id := requestid.FromContext(ctx); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+	if c.cfg.APIKey != "" {
+		req.Header.Set(c.authHeader(), c.authValue())
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		semconv.HTTPRequestMethodKey.String(http.MethodGet),
+		semconv.URLFull(apiURL),
+		semconv.ServerAddress(req.URL.Hostname()),
+	)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		fields := []interface{}{
+			"error", err,
+			"url", apiURL,
+			"request_id", requestid.FromContext(ctx),
+		}
+		c.logger.Errorw("Failed to make external API request", append(fields, tracing.LogFields(ctx)...)...)
+		return nil, true, fmt.Errorf("%w: %v", musicinfo.ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("%w: failed to read response: %v", musicinfo.ErrUnavailable, err)
+	}
+
+	c.logger.Debug("External API response received",
+		"status", resp.StatusCode,
+		"url", apiURL,
+		"body", string(body),
+	)
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnprocessableEntity:
+		return nil, false, musicinfo.ErrInvalidRequest
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, false, musicinfo.ErrNotFound
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return nil, true, fmt.Errorf("%w: unexpected status %d", musicinfo.ErrUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, false, fmt.Errorf("%w: unexpected status %d", musicinfo.ErrUnavailable, resp.StatusCode)
+	}
+
+	var detail models.SongDetail
+	if err := json.Unmarshal(body, &detail); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", musicinfo.ErrBadResponse, err)
+	}
+	return &detail, false, nil
+}
+
+// backoff doubles the delay with every attempt up to the configured maximum, the second half
+// of it is random so that clients retrying together spread out
+func (c *musicInfoClient) backoff(attempt int) time.Duration {
+	delay := c.cfg.RetryBaseDelay << attempt
+	if delay <= 0 || (c.cfg.RetryMaxDelay > 0 && delay > c.cfg.RetryMaxDelay) {
+		delay = c.cfg.RetryMaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// fits reports whether waiting delay still leaves a whole attempt before the caller's deadline,
+// without a timeout an attempt only needs some time left
+func (c *musicInfoClient) fits(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return true
+	}
+	remaining := time.Until(deadline) - delay
+	if c.cfg.Timeout > 0 {
+		return remaining >= c.cfg.Timeout
+	}
+	return remaining > 0
+}
+
+func (c *musicInfoClient) authHeader() string {
+	if c.cfg.AuthHeader == "" {
+		return defaultAuthHeader
+	}
+	return c.cfg.AuthHeader
+}
+
+func (c *musicInfoClient) authValue() string {
+	if c.cfg.AuthScheme == "" {
+		return c.cfg.APIKey
+	}
+	return c.cfg.AuthScheme + " " + c.cfg.APIKey
+}
+
+// outcome labels the result of a call in the music API metrics
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return metrics.OutcomeSuccess
+	case errors.Is(err, musicinfo.ErrInvalidRequest):
+		return metrics.OutcomeInvalidRequest
+	case errors.Is(err, musicinfo.ErrNotFound):
+		return metrics.OutcomeNotFound
+	case errors.Is(err, musicinfo.ErrBadResponse):
+		return metrics.OutcomeBadResponse
+	default:
+		return metrics.OutcomeUnavailable
+	}
+}
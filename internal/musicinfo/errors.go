@@ -0,0 +1,15 @@
+package musicinfo
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidRequest = errors.New("music API rejected the song or group name")
+	ErrNotFound       = errors.New("music API does not know the song")
+	ErrUnavailable    = errors.New("failed to fetch song details from music API")
+	ErrBadResponse    = errors.New("music API returned an invalid response")
+	// ErrCircuitOpen is returned without calling the API while it is considered down, it is an ErrUnavailable
+	ErrCircuitOpen = fmt.Errorf("%w: circuit breaker is open", ErrUnavailable)
+)
@@ -0,0 +1,12 @@
+package musicinfo
+
+import (
+	"context"
+	"musiclib/internal/models"
+)
+
+// Provider looks up song metadata in an external music catalogue. Failures are reported with
+// this package's errors: ErrNotFound, ErrInvalidRequest, ErrUnavailable and ErrBadResponse
+type Provider interface {
+	GetSongDetail(ctx context.Context, group string, song string) (*models.SongDetail, error)
+}
@@ -0,0 +1,17 @@
+package playlist
+
+import (
+	"net/http"
+)
+
+// Playlist HTTP Handlers interface
+type Handlers interface {
+	GetList(w http.ResponseWriter, r *http.Request)
+	GetByID(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	AddEntry(w http.ResponseWriter, r *http.Request)
+	RemoveEntry(w http.ResponseWriter, r *http.Request)
+	MoveEntry(w http.ResponseWriter, r *http.Request)
+}
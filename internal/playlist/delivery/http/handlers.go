@@ -0,0 +1,384 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"musiclib/config"
+	"musiclib/internal/models"
+	"musiclib/internal/playlist"
+	"musiclib/pkg/logger"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+)
+
+const defaultLimit = "10"
+const defaultOffset = "0"
+
+const playlistsPath = "/api/v1/playlists"
+
+// maxPlaylistNameLength matches the size of the playlists name column
+const maxPlaylistNameLength = 255
+
+// Playlist handlers
+type playlistHandlers struct {
+	cfg          *config.Config
+	playlistRepo playlist.Repository
+	logger       logger.Logger
+}
+
+// NewPlaylistHandlers Playlist handlers constructor
+func NewPlaylistHandlers(cfg *config.Config, logger logger.Logger, repo playlist.Repository) *playlistHandlers {
+	return &playlistHandlers{cfg: cfg, logger: logger, playlistRepo: repo}
+}
+
+// @Summary     List playlists
+// @Description Get paginated list of playlists ordered by name, without their entries
+// @Tags        playlists
+// @Accept      json
+// @Produce     json
+// @Param       limit query int false "Number of items to return (default: 10)"
+// @Param       offset query int false "Number of items to skip (default: 0)"
+// @Success     200 {array} models.Playlist
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     500 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /playlists [get]
+func (h *playlistHandlers) GetList(w http.ResponseWriter, r *http.Request) {
+	limit := r.URL.Query().Get("limit")
+	offset := r.URL.Query().Get("offset")
+
+	if limit == "" {
+		limit = defaultLimit
+	}
+	if offset == "" {
+		offset = defaultOffset
+	}
+
+	limitInt, err := strconv.Atoi(limit)
+	if err != nil {
+		http.Error(w, "Invalid limit value", http.StatusBadRequest)
+		return
+	}
+	offsetInt, err := strconv.Atoi(offset)
+	if err != nil {
+		http.Error(w, "Invalid offset value", http.StatusBadRequest)
+		return
+	}
+
+	playlists, err := h.playlistRepo.GetList(r.Context(), limitInt, offsetInt)
+	if err != nil {
+		h.logger.Error("Error getting playlists from repository", err)
+		http.Error(w, "Error getting playlists", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, playlists)
+}
+
+// @Summary     Get playlist
+// @Description Get a playlist with its entries in order, each entry embeds the song
+// @Tags        playlists
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Playlist ID"
+// @Success     200 {object} models.PlaylistDetail
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /playlists/{id} [get]
+func (h *playlistHandlers) GetByID(w http.ResponseWriter, r *http.Request) {
+	playlistID, ok := parsePlaylistID(w, r)
+	if !ok {
+		return
+	}
+
+	detail, err := h.playlistRepo.GetByID(r.Context(), playlistID)
+	if err != nil {
+		h.handleError(w, err, "Error getting playlist")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, detail)
+}
+
+// @Summary     Create playlist
+// @Description Create an empty playlist
+// @Tags        playlists
+// @Accept      json
+// @Produce     json
+// @Param       request body models.PlaylistRequest true "Playlist request"
+// @Success     201 {object} models.Playlist
+// @Failure     400 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /playlists [post]
+func (h *playlistHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodePlaylistRequest(w, r)
+	if !ok {
+		return
+	}
+
+	created, err := h.playlistRepo.Create(r.Context(), &models.Playlist{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		h.handleError(w, err, "Error creating playlist")
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("%s/%d", playlistsPath, created.ID))
+	h.writeJSON(w, http.StatusCreated, created)
+}
+
+// @Summary     Update playlist
+// @Description Rename a playlist or change its description
+// @Tags        playlists
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Playlist ID"
+// @Param       request body models.PlaylistRequest true "Playlist request"
+// @Success     200 {object} models.Playlist
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /playlists/{id} [put]
+func (h *playlistHandlers) Update(w http.ResponseWriter, r *http.Request) {
+	playlistID, ok := parsePlaylistID(w, r)
+	if !ok {
+		return
+	}
+
+	req, ok := decodePlaylistRequest(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := h.playlistRepo.Update(r.Context(), &models.Playlist{
+		ID:          playlistID,
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		h.handleError(w, err, "Error updating playlist")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, updated)
+}
+
+// @Summary     Delete playlist
+// @Description Delete a playlist with all its entries, the songs stay in the library
+// @Tags        playlists
+// @Accept      json
+// @Produce     plain
+// @Param       id path int true "Playlist ID"
+// @Success     200 {string} string "Playlist deleted successfully"
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /playlists/{id} [delete]
+func (h *playlistHandlers) Delete(w http.ResponseWriter, r *http.Request) {
+	playlistID, ok := parsePlaylistID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.playlistRepo.Delete(r.Context(), playlistID); err != nil {
+		h.handleError(w, err, "Error deleting playlist")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Playlist deleted successfully"))
+}
+
+// @Summary     Add playlist entry
+// @Description Append a song to the playlist, or insert it before the entry at position.
+// @Description The same song may be added several times.
+// @Tags        playlists
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Playlist ID"
+// @Param       request body models.AddPlaylistEntryRequest true "Entry request"
+// @Success     201 {object} models.PlaylistDetail
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /playlists/{id}/entries [post]
+func (h *playlistHandlers) AddEntry(w http.ResponseWriter, r *http.Request) {
+	playlistID, ok := parsePlaylistID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.AddPlaylistEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.SongID <= 0 {
+		http.Error(w, "songId is required", http.StatusBadRequest)
+		return
+	}
+	if req.Position < 0 {
+		http.Error(w, "Invalid position value", http.StatusBadRequest)
+		return
+	}
+
+	detail, err := h.playlistRepo.AddEntry(r.Context(), playlistID, req.SongID, req.Position)
+	if err != nil {
+		h.handleError(w, err, "Error adding playlist entry")
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, detail)
+}
+
+// @Summary     Remove playlist entry
+// @Description Remove an entry from the playlist, the entries after it move up
+// @Tags        playlists
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Playlist ID"
+// @Param       entryId path int true "Entry ID"
+// @Success     200 {object} models.PlaylistDetail
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /playlists/{id}/entries/{entryId} [delete]
+func (h *playlistHandlers) RemoveEntry(w http.ResponseWriter, r *http.Request) {
+	playlistID, ok := parsePlaylistID(w, r)
+	if !ok {
+		return
+	}
+	entryID, ok := parseEntryID(w, r)
+	if !ok {
+		return
+	}
+
+	detail, err := h.playlistRepo.RemoveEntry(r.Context(), playlistID, entryID)
+	if err != nil {
+		h.handleError(w, err, "Error removing playlist entry")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, detail)
+}
+
+// @Summary     Move playlist entry
+// @Description Move an entry to position, the entries in between shift by one
+// @Tags        playlists
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Playlist ID"
+// @Param       entryId path int true "Entry ID"
+// @Param       request body models.MovePlaylistEntryRequest true "Move request"
+// @Success     200 {object} models.PlaylistDetail
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /playlists/{id}/entries/{entryId}/move [post]
+func (h *playlistHandlers) MoveEntry(w http.ResponseWriter, r *http.Request) {
+	playlistID, ok := parsePlaylistID(w, r)
+	if !ok {
+		return
+	}
+	entryID, ok := parseEntryID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.MovePlaylistEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Position <= 0 {
+		http.Error(w, "Invalid position value", http.StatusBadRequest)
+		return
+	}
+
+	detail, err := h.playlistRepo.MoveEntry(r.Context(), playlistID, entryID, req.Position)
+	if err != nil {
+		h.handleError(w, err, "Error moving playlist entry")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, detail)
+}
+
+// handleError maps repository errors to HTTP responses
+func (h *playlistHandlers) handleError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, playlist.ErrNotFound):
+		http.Error(w, "Playlist not found", http.StatusNotFound)
+	case errors.Is(err, playlist.ErrEntryNotFound):
+		http.Error(w, "Playlist entry not found", http.StatusNotFound)
+	case errors.Is(err, playlist.ErrSongNotFound):
+		http.Error(w, "Song not found", http.StatusBadRequest)
+	case errors.Is(err, playlist.ErrInvalidPosition):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Error(message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+func (h *playlistHandlers) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
+
+func decodePlaylistRequest(w http.ResponseWriter, r *http.Request) (models.PlaylistRequest, bool) {
+	var req models.PlaylistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return req, false
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return req, false
+	}
+	if utf8.RuneCountInString(req.Name) > maxPlaylistNameLength {
+		http.Error(w, "Name is too long", http.StatusBadRequest)
+		return req, false
+	}
+
+	return req, true
+}
+
+func parsePlaylistID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	playlistID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid playlist ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return playlistID, true
+}
+
+func parseEntryID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	entryID, err := strconv.ParseInt(mux.Vars(r)["entryId"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid entry ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return entryID, true
+}
@@ -0,0 +1,19 @@
+package http
+
+import (
+	"musiclib/internal/playlist"
+
+	"github.com/gorilla/mux"
+)
+
+// Map playlists routes
+func MapPlaylistRoutes(playlistsGroup *mux.Router, h playlist.Handlers) {
+	playlistsGroup.HandleFunc("", h.GetList).Methods("GET")
+	playlistsGroup.HandleFunc("", h.Create).Methods("POST")
+	playlistsGroup.HandleFunc("/{id:[0-9]+}", h.GetByID).Methods("GET")
+	playlistsGroup.HandleFunc("/{id:[0-9]+}", h.Update).Methods("PUT")
+	playlistsGroup.HandleFunc("/{id:[0-9]+}", h.Delete).Methods("DELETE")
+	playlistsGroup.HandleFunc("/{id:[0-9]+}/entries", h.AddEntry).Methods("POST")
+	playlistsGroup.HandleFunc("/{id:[0-9]+}/entries/{entryId:[0-9]+}", h.RemoveEntry).Methods("DELETE")
+	playlistsGroup.HandleFunc("/{id:[0-9]+}/entries/{entryId:[0-9]+}/move", h.MoveEntry).Methods("POST")
+}
@@ -0,0 +1,10 @@
+package playlist
+
+import "errors"
+
+var (
+	ErrNotFound        = errors.New("playlist not found")
+	ErrEntryNotFound   = errors.New("playlist entry not found")
+	ErrSongNotFound    = errors.New("song not found")
+	ErrInvalidPosition = errors.New("position is out of range")
+)
@@ -0,0 +1,18 @@
+package playlist
+
+import (
+	"context"
+	"musiclib/internal/models"
+)
+
+// Repository interface
+type Repository interface {
+	GetList(ctx context.Context, limit int, offset int) ([]models.Playlist, error)
+	GetByID(ctx context.Context, id int) (*models.PlaylistDetail, error)
+	Create(ctx context.Context, playlist *models.Playlist) (*models.Playlist, error)
+	Update(ctx context.Context, playlist *models.Playlist) (*models.Playlist, error)
+	Delete(ctx context.Context, id int) error
+	AddEntry(ctx context.Context, id int, songID int, position int) (*models.PlaylistDetail, error)
+	RemoveEntry(ctx context.Context, id int, entryID int64) (*models.PlaylistDetail, error)
+	MoveEntry(ctx context.Context, id int, entryID int64, position int) (*models.PlaylistDetail, error)
+}
@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"musiclib/internal/models"
+	"musiclib/internal/playlist"
+	"musiclib/pkg/logger"
+	"musiclib/pkg/metrics"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type playlistRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+func NewPlaylistRepository(db *sqlx.DB, logger logger.Logger) *playlistRepository {
+	return &playlistRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *playlistRepository) GetList(ctx context.Context, limit int, offset int) ([]models.Playlist, error) {
+	defer metrics.ObserveQuery("playlist", "GetList")()
+	r.logger.Debug("Starting GetList in playlist repository", "limit", limit, "offset", offset)
+
+	playlists := make([]models.Playlist, 0)
+	if err := r.db.SelectContext(ctx, &playlists, getPlaylists, limit, offset); err != nil {
+		r.logger.Debug("Failed to get playlists", "error", err)
+		return nil, fmt.Errorf("failed to get playlists list: %w", err)
+	}
+
+	r.logger.Debug("Successfully retrieved playlists", "count", len(playlists))
+	return playlists, nil
+}
+
+// GetByID returns the playlist with its entries in order
+func (r *playlistRepository) GetByID(ctx context.Context, id int) (*models.PlaylistDetail, error) {
+	defer metrics.ObserveQuery("playlist", "GetByID")()
+	r.logger.Debug("Starting GetByID in playlist repository", "id", id)
+
+	// Read the playlist and its entries from the same snapshot
+	var detail *models.PlaylistDetail
+	err := r.inTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, func(tx *sqlx.Tx) error {
+		var err error
+		detail, err = r.getDetail(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+func (r *playlistRepository) Create(ctx context.Context, p *models.Playlist) (*models.Playlist, error) {
+	defer metrics.ObserveQuery("playlist", "Create")()
+	r.logger.Debug("Starting Create in playlist repository", "name", p.Name)
+
+	var id int
+	if err := r.db.QueryRowContext(ctx, createPlaylist, p.Name, p.Description).Scan(&id); err != nil {
+		r.logger.Debug("Failed to create playlist", "error", err)
+		return nil, fmt.Errorf("failed to create playlist: %w", err)
+	}
+
+	r.logger.Debug("Successfully created playlist", "id", id)
+	return r.getPlaylist(ctx, r.db, id)
+}
+
+func (r *playlistRepository) Update(ctx context.Context, p *models.Playlist) (*models.Playlist, error) {
+	defer metrics.ObserveQuery("playlist", "Update")()
+	r.logger.Debug("Starting Update in playlist repository", "id", p.ID, "name", p.Name)
+
+	var id int
+	if err := r.db.QueryRowContext(ctx, updatePlaylist, p.Name, p.Description, p.ID).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, playlist.ErrNotFound
+		}
+		r.logger.Debug("Failed to update playlist", "error", err, "id", p.ID)
+		return nil, fmt.Errorf("failed to update playlist: %w", err)
+	}
+
+	r.logger.Debug("Successfully updated playlist", "id", id)
+	return r.getPlaylist(ctx, r.db, id)
+}
+
+func (r *playlistRepository) Delete(ctx context.Context, id int) error {
+	defer metrics.ObserveQuery("playlist", "Delete")()
+	r.logger.Debug("Starting Delete in playlist repository", "id", id)
+
+	result, err := r.db.ExecContext(ctx, deletePlaylist, id)
+	if err != nil {
+		r.logger.Debug("Failed to delete playlist", "error", err, "id", id)
+		return fmt.Errorf("failed to delete playlist: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return playlist.ErrNotFound
+	}
+
+	r.logger.Debug("Successfully deleted playlist", "id", id)
+	return nil
+}
+
+// AddEntry inserts the song before the entry at position, a zero position appends it
+func (r *playlistRepository) AddEntry(ctx context.Context, id int, songID int, position int) (*models.PlaylistDetail, error) {
+	defer metrics.ObserveQuery("playlist", "AddEntry")()
+	r.logger.Debug("Starting AddEntry in playlist repository", "id", id, "songID", songID, "position", position)
+
+	return r.edit(ctx, id, func(tx *sqlx.Tx, count int) error {
+		if position == 0 {
+			position = count + 1
+		}
+		if position < 1 || position > count+1 {
+			return playlist.ErrInvalidPosition
+		}
+
+		// Keep the song from being purged until the entry is committed
+		if err := tx.QueryRowContext(ctx, lockSong, songID).Scan(&songID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return playlist.ErrSongNotFound
+			}
+			return fmt.Errorf("failed to lock song: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, shiftEntries, id, position, count, 1); err != nil {
+			return fmt.Errorf("failed to shift entries: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, insertEntry, id, songID, position); err != nil {
+			return fmt.Errorf("failed to insert entry: %w", err)
+		}
+		return nil
+	})
+}
+
+// RemoveEntry deletes the entry and closes the gap it leaves
+func (r *playlistRepository) RemoveEntry(ctx context.Context, id int, entryID int64) (*models.PlaylistDetail, error) {
+	defer metrics.ObserveQuery("playlist", "RemoveEntry")()
+	r.logger.Debug("Starting RemoveEntry in playlist repository", "id", id, "entryID", entryID)
+
+	return r.edit(ctx, id, func(tx *sqlx.Tx, count int) error {
+		from, err := r.entryPosition(ctx, tx, id, entryID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, deleteEntry, id, entryID); err != nil {
+			return fmt.Errorf("failed to delete entry: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, shiftEntries, id, from+1, count, -1); err != nil {
+			return fmt.Errorf("failed to shift entries: %w", err)
+		}
+		return nil
+	})
+}
+
+// MoveEntry puts the entry at position, the entries in between move by one
+func (r *playlistRepository) MoveEntry(ctx context.Context, id int, entryID int64, position int) (*models.PlaylistDetail, error) {
+	defer metrics.ObserveQuery("playlist", "MoveEntry")()
+	r.logger.Debug("Starting MoveEntry in playlist repository", "id", id, "entryID", entryID, "position", position)
+
+	return r.edit(ctx, id, func(tx *sqlx.Tx, count int) error {
+		from, err := r.entryPosition(ctx, tx, id, entryID)
+		if err != nil {
+			return err
+		}
+		if position < 1 || position > count {
+			return playlist.ErrInvalidPosition
+		}
+
+		switch {
+		case position < from:
+			_, err = tx.ExecContext(ctx, shiftEntries, id, position, from-1, 1)
+		case position > from:
+			_, err = tx.ExecContext(ctx, shiftEntries, id, from+1, position, -1)
+		default:
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to shift entries: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, setEntryPosition, id, entryID, position); err != nil {
+			return fmt.Errorf("failed to move entry: %w", err)
+		}
+		return nil
+	})
+}
+
+// edit runs fn with the playlist locked and its positions compacted to 1..count,
+// concurrent edits of the same playlist wait for each other so positions never clash
+func (r *playlistRepository) edit(ctx context.Context, id int, fn func(tx *sqlx.Tx, count int) error) (*models.PlaylistDetail, error) {
+	var detail *models.PlaylistDetail
+	err := r.inTx(ctx, nil, func(tx *sqlx.Tx) error {
+		if err := tx.QueryRowContext(ctx, lockPlaylist, id).Scan(&id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return playlist.ErrNotFound
+			}
+			return fmt.Errorf("failed to lock playlist: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, compactEntries, id); err != nil {
+			return fmt.Errorf("failed to compact entries: %w", err)
+		}
+
+		var count int
+		if err := tx.GetContext(ctx, &count, countEntries, id); err != nil {
+			return fmt.Errorf("failed to count entries: %w", err)
+		}
+
+		if err := fn(tx, count); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, touchPlaylist, id); err != nil {
+			return fmt.Errorf("failed to touch playlist: %w", err)
+		}
+
+		var err error
+		detail, err = r.getDetail(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		r.logger.Debug("Failed to edit playlist", "error", err, "id", id)
+		return nil, err
+	}
+	return detail, nil
+}
+
+func (r *playlistRepository) entryPosition(ctx context.Context, tx *sqlx.Tx, id int, entryID int64) (int, error) {
+	var position int
+	if err := tx.GetContext(ctx, &position, getEntryPosition, id, entryID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, playlist.ErrEntryNotFound
+		}
+		return 0, fmt.Errorf("failed to get entry: %w", err)
+	}
+	return position, nil
+}
+
+func (r *playlistRepository) getPlaylist(ctx context.Context, q sqlx.QueryerContext, id int) (*models.Playlist, error) {
+	var p models.Playlist
+	if err := sqlx.GetContext(ctx, q, &p, getPlaylistByID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, playlist.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get playlist: %w", err)
+	}
+	return &p, nil
+}
+
+func (r *playlistRepository) getDetail(ctx context.Context, q sqlx.QueryerContext, id int) (*models.PlaylistDetail, error) {
+	p, err := r.getPlaylist(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &models.PlaylistDetail{Playlist: *p, Entries: make([]models.PlaylistEntry, 0)}
+	if err := sqlx.SelectContext(ctx, q, &detail.Entries, getPlaylistEntries, id); err != nil {
+		return nil, fmt.Errorf("failed to get playlist entries: %w", err)
+	}
+
+	// Positions may have gaps until the next edit, entries are numbered as they come
+	for i := range detail.Entries {
+		detail.Entries[i].Position = i + 1
+	}
+	return detail, nil
+}
+
+func (r *playlistRepository) inTx(ctx context.Context, opts *sql.TxOptions, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+const playlistColumns = `p.id, p.name, p.description, p.created_at, p.updated_at,
+    (SELECT COUNT(*) FROM playlist_entries e WHERE e.playlist_id = p.id) AS entries_count`
+
+const getPlaylists = `SELECT ` + playlistColumns + ` FROM playlists p ORDER BY p.name, p.id LIMIT $1 OFFSET $2`
+
+const getPlaylistByID = `SELECT ` + playlistColumns + ` FROM playlists p WHERE p.id = $1`
+
+const getPlaylistEntries = `
+    SELECT e.id, e.position, e.added_at,
+           s.id AS "song.id", COALESCE(s.artist_id, 0) AS "song.artist_id", COALESCE(a.name, '') AS "song.group_name",
+           COALESCE(s.song, '') AS "song.song",
+           format_release_date(s.release_date, s.release_date_precision) AS "song.release_date",
+           COALESCE(s.text, '') AS "song.text", COALESCE(s.link, '') AS "song.link",
+           COALESCE(s.album_id, 0) AS "song.album_id", COALESCE(s.disc_number, 0) AS "song.disc_number",
+           COALESCE(s.track_number, 0) AS "song.track_number", s.deleted_at AS "song.deleted_at"
+    FROM playlist_entries e
+    JOIN songs s ON s.id = e.song_id
+    LEFT JOIN artists a ON a.id = s.artist_id
+    WHERE e.playlist_id = $1
+    ORDER BY e.position`
+
+const createPlaylist = `
+    INSERT INTO playlists (name, description)
+    VALUES ($1, $2)
+    RETURNING id`
+
+const updatePlaylist = `
+    UPDATE playlists
+    SET name = $1,
+        description = $2,
+        updated_at = NOW()
+    WHERE id = $3
+    RETURNING id`
+
+const deletePlaylist = `DELETE FROM playlists WHERE id = $1`
+
+// lockPlaylist serializes edits of a playlist, every entry change takes this lock first
+const lockPlaylist = `SELECT id FROM playlists WHERE id = $1 FOR UPDATE`
+
+const touchPlaylist = `UPDATE playlists SET updated_at = NOW() WHERE id = $1`
+
+// compactEntries closes the gaps left by songs deleted from the library
+const compactEntries = `
+    UPDATE playlist_entries e
+    SET position = n.position
+    FROM (
+        SELECT id, row_number() OVER (ORDER BY position, id) AS position
+        FROM playlist_entries
+        WHERE playlist_id = $1
+    ) n
+    WHERE e.id = n.id AND e.position <> n.position`
+
+const countEntries = `SELECT COUNT(*) FROM playlist_entries WHERE playlist_id = $1`
+
+const getEntryPosition = `SELECT position FROM playlist_entries WHERE playlist_id = $1 AND id = $2`
+
+// shiftEntries moves the entries between two positions, both inclusive, by $4
+const shiftEntries = `
+    UPDATE playlist_entries
+    SET position = position + $4
+    WHERE playlist_id = $1 AND position BETWEEN $2 AND $3`
+
+const lockSong = `SELECT id FROM songs WHERE id = $1 AND deleted_at IS NULL FOR KEY SHARE`
+
+const insertEntry = `INSERT INTO playlist_entries (playlist_id, song_id, position) VALUES ($1, $2, $3)`
+
+const setEntryPosition = `UPDATE playlist_entries SET position = $3 WHERE playlist_id = $1 AND id = $2`
+
+const deleteEntry = `DELETE FROM playlist_entries WHERE playlist_id = $1 AND id = $2`
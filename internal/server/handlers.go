@@ -1,22 +1,139 @@
 package server
 
 import (
+	"net/http"
+
 	"github.com/gorilla/mux"
 
+	albumHttp "musiclib/internal/album/delivery/http"
+	albumRepository "musiclib/internal/album/repository"
+	apiKeyHttp "musiclib/internal/apikey/delivery/http"
+	apiKeyRepository "musiclib/internal/apikey/repository"
+	artistHttp "musiclib/internal/artist/delivery/http"
+	artistRepository "musiclib/internal/artist/repository"
+	"musiclib/internal/auth"
+	authHttp "musiclib/internal/auth/delivery/http"
+	authRepository "musiclib/internal/auth/repository"
+	healthHttp "musiclib/internal/health/delivery/http"
+	importHttp "musiclib/internal/imports/delivery/http"
+	importRepository "musiclib/internal/imports/repository"
+	"musiclib/internal/middleware"
+	"musiclib/internal/models"
+	playlistHttp "musiclib/internal/playlist/delivery/http"
+	playlistRepository "musiclib/internal/playlist/repository"
 	songHttp "musiclib/internal/song/delivery/http"
 	"musiclib/internal/song/repository"
+	tagHttp "musiclib/internal/tag/delivery/http"
+	tagRepository "musiclib/internal/tag/repository"
+	"musiclib/pkg/audit"
 )
 
+// libraryPolicy guards the library: viewers read, editors add and update, only admins delete
+var libraryPolicy = middleware.Policy{
+	http.MethodGet:    models.RoleViewer,
+	http.MethodHead:   models.RoleViewer,
+	http.MethodPost:   models.RoleEditor,
+	http.MethodPut:    models.RoleEditor,
+	http.MethodPatch:  models.RoleEditor,
+	http.MethodDelete: models.RoleAdmin,
+}
+
+// curationPolicy guards playlists and song tags, removing an entry or a tag is an edit rather than a deletion
+var curationPolicy = middleware.Policy{
+	http.MethodGet:    models.RoleViewer,
+	http.MethodHead:   models.RoleViewer,
+	http.MethodPost:   models.RoleEditor,
+	http.MethodPut:    models.RoleEditor,
+	http.MethodPatch:  models.RoleEditor,
+	http.MethodDelete: models.RoleEditor,
+}
+
+// adminPolicy restricts user and API key management to admins
+var adminPolicy = middleware.Policy{}
+
 // MapHandlers Map Server Handlers
 func (s *Server) MapHandlers(router *mux.Router) error {
+	tokens, err := auth.NewTokens(s.cfg.Auth)
+	if err != nil {
+		return err
+	}
+
 	songRepo := repository.NewSongRepository(s.db, s.logger)
+	artistRepo := artistRepository.NewArtistRepository(s.db, s.logger)
+	albumRepo := albumRepository.NewAlbumRepository(s.db, s.logger)
+	importRepo := importRepository.NewImportRepository(s.db, s.logger)
+	tagRepo := tagRepository.NewTagRepository(s.db, s.logger)
+	playlistRepo := playlistRepository.NewPlaylistRepository(s.db, s.logger)
+	authRepo := authRepository.NewAuthRepository(s.db, s.logger)
+	apiKeyRepo := apiKeyRepository.NewAPIKeyRepository(s.db, s.logger)
+
+	songHandlers := songHttp.NewSongHandlers(s.cfg, s.logger, songRepo, artistRepo, s.musicInfo)
+	artistHandlers := artistHttp.NewArtistHandlers(s.cfg, s.logger, artistRepo)
+	albumHandlers := albumHttp.NewAlbumHandlers(s.cfg, s.logger, albumRepo, artistRepo)
+	importHandlers := importHttp.NewImportHandlers(s.cfg, s.logger, importRepo)
+	tagHandlers := tagHttp.NewTagHandlers(s.cfg, s.logger, tagRepo)
+	playlistHandlers := playlistHttp.NewPlaylistHandlers(s.cfg, s.logger, playlistRepo)
+	authHandlers := authHttp.NewAuthHandlers(s.cfg, s.logger, authRepo, tokens)
+	apiKeyHandlers := apiKeyHttp.NewAPIKeyHandlers(s.cfg, s.logger, apiKeyRepo)
+	healthHandlers := healthHttp.NewHealthHandlers(s.cfg, s.logger, s.health)
+
+	authMiddleware := middleware.NewAuthMiddleware(tokens, apiKeyRepo, s.logger)
+	rateLimiter := middleware.NewRateLimiter(s.cfg.RateLimit, s.logger)
+
+	healthHttp.MapHealthRoutes(router, healthHandlers)
 
-	songHandlers := songHttp.NewSongHandlers(s.cfg, s.logger, songRepo)
+	// Every matched route reports its template to the access log and gets a deadline,
+	// exports push their own write deadline forward while they stream
+	router.Use(middleware.RecordRoute, middleware.Timeout(s.cfg.Server.RequestTimeout, songHttp.RouteExport))
 
 	apiRouter := router.PathPrefix("/api/v1").Subrouter()
+	apiRouter.Use(audit.Middleware)
 
-	songsGroup := apiRouter.PathPrefix("/songs").Subrouter()
+	// Anonymous clients are limited by IP address, as are the failed sign ins of any client
+	authGroup := apiRouter.PathPrefix("/auth").Subrouter()
+	authGroup.Use(rateLimiter.LimitAuthFailures, rateLimiter.Limit)
+	register := func(next http.Handler) http.Handler { return next }
+	if !s.cfg.Auth.AllowRegistration {
+		register = func(next http.Handler) http.Handler {
+			return authMiddleware.Authenticate(authMiddleware.Authorize(adminPolicy, "")(next))
+		}
+	}
+	authHttp.MapAuthRoutes(authGroup, authHandlers, authMiddleware.Authenticate, register)
+
+	// Everything else needs a signed in user whose role the group's policy allows, or an API key with
+	// the matching scope on the group's resource. Failed authentications are limited by IP address,
+	// callers are rate limited once they are known
+	guard := func(group *mux.Router, policy middleware.Policy, resource string) *mux.Router {
+		group.Use(rateLimiter.LimitAuthFailures, authMiddleware.Authenticate, rateLimiter.Limit, authMiddleware.Authorize(policy, resource))
+		return group
+	}
+
+	songTagsGroup := guard(apiRouter.PathPrefix("/songs").Subrouter(), curationPolicy, "")
+	tagHttp.MapSongTagRoutes(songTagsGroup, tagHandlers)
+
+	songsGroup := guard(apiRouter.PathPrefix("/songs").Subrouter(), libraryPolicy, models.ResourceSongs)
 	songHttp.MapSongRoutes(songsGroup, songHandlers)
 
+	artistsGroup := guard(apiRouter.PathPrefix("/artists").Subrouter(), libraryPolicy, "")
+	artistHttp.MapArtistRoutes(artistsGroup, artistHandlers)
+
+	albumsGroup := guard(apiRouter.PathPrefix("/albums").Subrouter(), libraryPolicy, "")
+	albumHttp.MapAlbumRoutes(albumsGroup, albumHandlers)
+
+	importsGroup := guard(apiRouter.PathPrefix("/imports").Subrouter(), libraryPolicy, "")
+	importHttp.MapImportRoutes(importsGroup, importHandlers)
+
+	tagsGroup := guard(apiRouter.PathPrefix("/tags").Subrouter(), libraryPolicy, "")
+	tagHttp.MapTagRoutes(tagsGroup, tagHandlers)
+
+	playlistsGroup := guard(apiRouter.PathPrefix("/playlists").Subrouter(), curationPolicy, "")
+	playlistHttp.MapPlaylistRoutes(playlistsGroup, playlistHandlers)
+
+	usersGroup := guard(apiRouter.PathPrefix("/users").Subrouter(), adminPolicy, "")
+	authHttp.MapUserRoutes(usersGroup, authHandlers)
+
+	apiKeysGroup := guard(apiRouter.PathPrefix("/api-keys").Subrouter(), adminPolicy, "")
+	apiKeyHttp.MapAPIKeyRoutes(apiKeysGroup, apiKeyHandlers)
+
 	return nil
 }
@@ -2,8 +2,16 @@ package server
 
 import (
 	"context"
+	"errors"
 	"musiclib/config"
+	"musiclib/internal/auth"
+	authRepository "musiclib/internal/auth/repository"
+	"musiclib/internal/health"
+	"musiclib/internal/middleware"
+	"musiclib/internal/musicinfo"
+	musicInfoClient "musiclib/internal/musicinfo/client"
 	"musiclib/pkg/logger"
+	"musiclib/pkg/metrics"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
@@ -25,29 +33,65 @@ type Server struct {
 	cfg    *config.Config
 	db     *sqlx.DB
 	logger logger.Logger
+	health *health.Checker
+	// musicInfo is shared by the handlers and the workers, so they see the same circuit breaker
+	musicInfo musicinfo.Provider
 }
 
 func NewServer(cfg *config.Config, db *sqlx.DB, logger logger.Logger) *Server {
-	return &Server{cfg: cfg, db: db, logger: logger}
+	checker := health.NewChecker(cfg.Health.CheckTimeout,
+		health.Database(db),
+		health.Migrations(db),
+		health.MusicAPI(cfg.MusicApi.URL),
+	)
+	return &Server{
+		cfg:       cfg,
+		db:        db,
+		logger:    logger,
+		health:    checker,
+		musicInfo: musicInfoClient.NewMusicInfoClient(cfg.MusicApi, logger),
+	}
 }
 
 func (s *Server) Run() error {
+	if err := metrics.RegisterDB(s.db.DB, s.cfg.Database.DBName); err != nil {
+		return err
+	}
+
+	admin, err := auth.EnsureAdmin(context.Background(), s.cfg.Auth, authRepository.NewAuthRepository(s.db, s.logger))
+	if err != nil {
+		return err
+	}
+	if admin != nil {
+		s.logger.Infof("Created admin %s", admin.Username)
+	}
+
 	router := mux.NewRouter()
+	router.Handle("/metrics", metrics.Handler()).Methods(http.MethodGet)
 	router.PathPrefix("/swagger/").Handler(httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"),
 	))
 
+	// Wrapped from the inside out: panics are recovered before they reach the metrics and the access log,
+	// which log within the request span and see the request ID
+	var handler http.Handler = router
+	handler = middleware.Recover(s.logger)(handler)
+	handler = middleware.Metrics(handler)
+	handler = middleware.AccessLog(s.logger)(handler)
+	handler = middleware.Tracing(handler)
+	handler = middleware.RequestID(handler)
+
 	server := &http.Server{
 		Addr:           s.cfg.Server.Port,
 		ReadTimeout:    time.Second * s.cfg.Server.ReadTimeout,
 		WriteTimeout:   time.Second * s.cfg.Server.WriteTimeout,
 		MaxHeaderBytes: maxHeaderBytes,
-		Handler:        router,
+		Handler:        handler,
 	}
 
 	go func() {
 		s.logger.Infof("Server is listening on PORT: %s", s.cfg.Server.Port)
-		if err := server.ListenAndServe(); err != nil {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			s.logger.Fatalf("Error starting Server: ", err)
 		}
 	}()
@@ -56,14 +100,29 @@ func (s *Server) Run() error {
 		return err
 	}
 
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	workersDone := s.startWorkers(workersCtx)
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
 	<-quit
 
+	// Fail readiness first and give load balancers time to notice before connections are refused
+	s.health.SetShuttingDown()
+	s.logger.Infof("Shutting down, reporting not ready for %s", s.cfg.Health.ShutdownDelay)
+	time.Sleep(s.cfg.Health.ShutdownDelay)
+
 	ctx, shutdown := context.WithTimeout(context.Background(), ctxTimeout*time.Second)
 	defer shutdown()
 
+	stopWorkers()
+	select {
+	case <-workersDone:
+	case <-ctx.Done():
+		s.logger.Error("Background workers did not stop in time")
+	}
+
 	s.logger.Info("Server Exited Properly")
 	return server.Shutdown(ctx)
 }
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	artistRepository "musiclib/internal/artist/repository"
+	importRepository "musiclib/internal/imports/repository"
+	importWorker "musiclib/internal/imports/worker"
+	"musiclib/internal/song/repository"
+	"musiclib/internal/song/worker"
+)
+
+// startWorkers launches the background jobs, the returned channel is closed once all of them stop after ctx is canceled
+func (s *Server) startWorkers(ctx context.Context) <-chan struct{} {
+	songRepo := repository.NewSongRepository(s.db, s.logger)
+	artistRepo := artistRepository.NewArtistRepository(s.db, s.logger)
+	importRepo := importRepository.NewImportRepository(s.db, s.logger)
+
+	jobs := []func(context.Context){
+		worker.NewTrashPurger(s.cfg.Trash, songRepo, s.logger).Run,
+		worker.NewStanzaBackfill(songRepo, s.logger).Run,
+		worker.NewEnricher(s.cfg.Enrichment, songRepo, s.musicInfo, s.logger).Run,
+		importWorker.NewProcessor(s.cfg.Imports, importRepo, songRepo, artistRepo, s.musicInfo, s.logger).Run,
+	}
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func(run func(context.Context)) {
+			defer wg.Done()
+			run(ctx)
+		}(job)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return done
+}
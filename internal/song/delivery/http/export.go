@@ -0,0 +1,111 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"musiclib/internal/models"
+	"strconv"
+)
+
+// songExportWriter encodes exported songs one at a time
+type songExportWriter interface {
+	contentType() string
+	begin() error
+	write(s models.Song) error
+	flush() error
+	end() error
+}
+
+type ndjsonExportWriter struct {
+	w io.Writer
+}
+
+func (e *ndjsonExportWriter) contentType() string { return "application/x-ndjson" }
+func (e *ndjsonExportWriter) begin() error        { return nil }
+func (e *ndjsonExportWriter) flush() error        { return nil }
+func (e *ndjsonExportWriter) end() error          { return nil }
+
+func (e *ndjsonExportWriter) write(s models.Song) error {
+	// Encode terminates every value with a newline
+	return json.NewEncoder(e.w).Encode(s)
+}
+
+// jsonExportWriter writes a single JSON array without holding it in memory
+type jsonExportWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonExportWriter) contentType() string { return "application/json" }
+func (e *jsonExportWriter) flush() error        { return nil }
+
+func (e *jsonExportWriter) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonExportWriter) write(s models.Song) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonExportWriter) end() error {
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
+
+// csvExportWriter writes a header row followed by one record per song
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+var csvExportHeader = []string{
+	"id", "group", "song", "release_date", "text", "link", "album_id", "disc_number", "track_number",
+}
+
+func (e *csvExportWriter) contentType() string { return "text/csv; charset=utf-8" }
+
+func (e *csvExportWriter) begin() error {
+	return e.w.Write(csvExportHeader)
+}
+
+func (e *csvExportWriter) write(s models.Song) error {
+	return e.w.Write([]string{
+		strconv.Itoa(s.ID),
+		s.Group,
+		s.Song,
+		s.ReleaseDate.String(),
+		s.Text,
+		s.Link,
+		optionalInt(s.AlbumID),
+		optionalInt(s.DiscNumber),
+		optionalInt(s.TrackNumber),
+	})
+}
+
+func (e *csvExportWriter) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExportWriter) end() error {
+	return e.flush()
+}
+
+func optionalInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.Itoa(v)
+}
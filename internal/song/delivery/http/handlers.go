@@ -1,19 +1,28 @@
 package http
 
 import (
-	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"mime"
 	"musiclib/config"
+	"musiclib/internal/artist"
+	"musiclib/internal/auth"
 	"musiclib/internal/models"
+	"musiclib/internal/musicinfo"
 	"musiclib/internal/song"
 	"musiclib/pkg/logger"
 	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+
+	"github.com/gorilla/mux"
 )
 
 const defaultLimit = "10"
@@ -21,31 +30,69 @@ const defaultOffset = "0"
 const defaultSortBy = "id"
 const defaultSortOrder = "asc"
 
+// songsPath is where the songs collection is mounted
+const songsPath = "/api/v1/songs"
+
+// Export formats and streaming settings
+const (
+	exportFormatNDJSON = "ndjson"
+	exportFormatJSON   = "json"
+	exportFormatCSV    = "csv"
+	exportFlushEvery   = 100
+	exportWriteTimeout = 30 * time.Second
+)
+
+// idempotencyKeyHeader lets clients retry Add without creating the song twice
+const idempotencyKeyHeader = "Idempotency-Key"
+const maxIdempotencyKeyLength = 255
+
+// mergePatchContentType is the media type of JSON Merge Patch documents
+const mergePatchContentType = "application/merge-patch+json"
+
+// respondAsync is the Prefer header token asking Add to store the song right away and fetch the details later
+const respondAsync = "respond-async"
+
 // Song handlers
 type songHandlers struct {
-	cfg      *config.Config
-	songRepo song.Repository
-	logger   logger.Logger
+	cfg        *config.Config
+	songRepo   song.Repository
+	artistRepo artist.Repository
+	musicInfo  musicinfo.Provider
+	logger     logger.Logger
 }
 
 // NewSongHandlers Song handlers constructor
-func NewSongHandlers(cfg *config.Config, logger logger.Logger, repo song.Repository) *songHandlers {
-	return &songHandlers{cfg: cfg, logger: logger, songRepo: repo}
+func NewSongHandlers(cfg *config.Config, logger logger.Logger, repo song.Repository, artistRepo artist.Repository, musicInfo musicinfo.Provider) *songHandlers {
+	return &songHandlers{cfg: cfg, logger: logger, songRepo: repo, artistRepo: artistRepo, musicInfo: musicInfo}
 }
 
 // @Summary     List songs
-// @Description Get paginated and sorted list of songs
+// @Description Get paginated and sorted list of songs. Pagination is offset based by default;
+// @Description passing cursor switches to keyset pagination and wraps the result in models.SongPage.
 // @Tags        songs
 // @Accept      json
 // @Produce     json
+// @Param       group query string false "Exact group name, case-insensitive"
+// @Param       group_contains query string false "Substring of the group name, case-insensitive"
+// @Param       song_contains query string false "Substring of the song title, case-insensitive"
+// @Param       text_contains query string false "Substring of the song text, case-insensitive"
+// @Param       released_from query string false "Released on or after this date (YYYY-MM-DD, YYYY-MM or YYYY)"
+// @Param       released_to query string false "Released on or before this date (YYYY-MM-DD, YYYY-MM or YYYY)"
+// @Param       has_link query bool false "Only songs with (true) or without (false) a link"
+// @Param       tag query string false "Only songs carrying all of these tags, repeated or comma-separated"
+// @Param       tag_any query string false "Only songs carrying at least one of these tags, repeated or comma-separated"
+// @Param       tag_none query string false "Only songs carrying none of these tags, repeated or comma-separated"
 // @Param       sort_by query string false "Field to sort by: group_name, song, id, release_date (default: id)"
 // @Param       sort_order query string false "Sort order: asc or desc (default: asc)"
 // @Param       limit query int false "Number of items to return (default: 10)"
 // @Param       offset query int false "Number of items to skip (default: 0)"
-// @Success     200 {array} models.Song
+// @Param       cursor query string false "Switches to keyset pagination: pass an empty value for the first page, then next_cursor from the previous response"
+// @Success     200 {array} models.Song "Offset pagination, with cursor the body is models.SongPage"
 // @Failure     400 {object} models.ErrorResponse
 // @Failure     500 {object} models.ErrorResponse
-// @Router      /songs/list [get]
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs [get]
 func (h *songHandlers) GetList(w http.ResponseWriter, r *http.Request) {
 	h.logger.Debug("Starting GetList handler")
 	// Get query parameters for sorting and pagination
@@ -61,6 +108,32 @@ func (h *songHandlers) GetList(w http.ResponseWriter, r *http.Request) {
 		"offset", offset,
 	)
 
+	// A cursor parameter switches to keyset pagination, an empty value requests the first page
+	cursorMode := r.URL.Query().Has("cursor")
+	var after *models.SongCursor
+	if cursorMode {
+		if offset != "" {
+			http.Error(w, "offset cannot be combined with cursor", http.StatusBadRequest)
+			return
+		}
+
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			var err error
+			after, err = models.DecodeSongCursor(cursor)
+			if err != nil {
+				http.Error(w, "Invalid cursor value", http.StatusBadRequest)
+				return
+			}
+
+			// The cursor remembers the ordering it was issued for
+			if (sortBy != "" && sortBy != after.SortBy) || (sortOrder != "" && sortOrder != after.SortOrder) {
+				http.Error(w, "sort_by and sort_order must match the cursor", http.StatusBadRequest)
+				return
+			}
+			sortBy, sortOrder = after.SortBy, after.SortOrder
+		}
+	}
+
 	// Validate and set default values for query parameters
 	if sortBy == "" {
 		sortBy = defaultSortBy
@@ -75,6 +148,15 @@ func (h *songHandlers) GetList(w http.ResponseWriter, r *http.Request) {
 		offset = defaultOffset
 	}
 
+	if !models.IsValidSongSortField(sortBy) {
+		http.Error(w, "Invalid sort_by value", http.StatusBadRequest)
+		return
+	}
+	if sortOrder != "asc" && sortOrder != "desc" {
+		http.Error(w, "Invalid sort_order value", http.StatusBadRequest)
+		return
+	}
+
 	h.logger.Debug("Normalized query parameters",
 		"sortBy", sortBy,
 		"sortOrder", sortOrder,
@@ -84,13 +166,13 @@ func (h *songHandlers) GetList(w http.ResponseWriter, r *http.Request) {
 
 	// Convert query parameters to integers
 	limitInt, err := strconv.Atoi(limit)
-	if err != nil {
+	if err != nil || limitInt <= 0 {
 		h.logger.Error("Invalid limit value", err)
 		http.Error(w, "Invalid limit value", http.StatusBadRequest)
 		return
 	}
 	offsetInt, err := strconv.Atoi(offset)
-	if err != nil {
+	if err != nil || offsetInt < 0 {
 		h.logger.Error("Invalid offset value", err)
 		http.Error(w, "Invalid offset value", http.StatusBadRequest)
 		return
@@ -101,8 +183,27 @@ func (h *songHandlers) GetList(w http.ResponseWriter, r *http.Request) {
 		"offsetInt", offsetInt,
 	)
 
+	filter, err := parseSongFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	listQuery := models.SongListQuery{
+		Filter:    filter,
+		SortBy:    sortBy,
+		SortOrder: sortOrder,
+		Limit:     limitInt,
+		Offset:    offsetInt,
+		After:     after,
+	}
+	if cursorMode {
+		// Fetch one extra row to know whether there is a next page
+		listQuery.Limit++
+	}
+
 	// Get the list of songs from the repository
-	songs, err := h.songRepo.GetList(sortBy, sortOrder, limitInt, offsetInt)
+	songs, err := h.songRepo.GetList(r.Context(), listQuery)
 	if err != nil {
 		h.logger.Error("Error getting songs from repository", err)
 		http.Error(w, "Error getting songs", http.StatusInternalServerError)
@@ -113,8 +214,19 @@ func (h *songHandlers) GetList(w http.ResponseWriter, r *http.Request) {
 		"count", len(songs),
 	)
 
+	// Offset mode keeps the plain array response for backward compatibility
+	var response interface{} = songs
+	if cursorMode {
+		page := models.SongPage{Songs: songs}
+		if len(songs) > limitInt {
+			page.Songs = songs[:limitInt]
+			page.NextCursor = models.NewSongCursor(page.Songs[limitInt-1], sortBy, sortOrder).Encode()
+		}
+		response = page
+	}
+
 	// Marshal the songs to JSON
-	songsJSON, err := json.Marshal(songs)
+	songsJSON, err := json.Marshal(response)
 	if err != nil {
 		h.logger.Error("Error marshaling songs to JSON", err)
 		http.Error(w, "Error marshaling songs to JSON", http.StatusInternalServerError)
@@ -130,37 +242,293 @@ func (h *songHandlers) GetList(w http.ResponseWriter, r *http.Request) {
 	w.Write(songsJSON)
 }
 
-// @Summary     Get song text
-// @Description Get the text of a song by ID with pagination
+// @Summary     Export songs
+// @Description Stream all songs matching the list filters, ordered by ID. Lyrics are exported with real line breaks.
+// @Tags        songs
+// @Produce     json,application/x-ndjson,text/csv
+// @Param       format query string false "ndjson (default), csv or json"
+// @Param       group query string false "Exact group name, case-insensitive"
+// @Param       group_contains query string false "Substring of the group name, case-insensitive"
+// @Param       song_contains query string false "Substring of the song title, case-insensitive"
+// @Param       text_contains query string false "Substring of the song text, case-insensitive"
+// @Param       released_from query string false "Released on or after this date (YYYY-MM-DD, YYYY-MM or YYYY)"
+// @Param       released_to query string false "Released on or before this date (YYYY-MM-DD, YYYY-MM or YYYY)"
+// @Param       has_link query bool false "Only songs with (true) or without (false) a link"
+// @Param       tag query string false "Only songs carrying all of these tags, repeated or comma-separated"
+// @Param       tag_any query string false "Only songs carrying at least one of these tags, repeated or comma-separated"
+// @Param       tag_none query string false "Only songs carrying none of these tags, repeated or comma-separated"
+// @Success     200 {array} models.Song
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     429 {object} models.RateLimitResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/export [get]
+func (h *songHandlers) Export(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = exportFormatNDJSON
+	}
+
+	var writer songExportWriter
+	switch format {
+	case exportFormatNDJSON:
+		writer = &ndjsonExportWriter{w: w}
+	case exportFormatJSON:
+		writer = &jsonExportWriter{w: w}
+	case exportFormatCSV:
+		writer = &csvExportWriter{w: csv.NewWriter(w)}
+	default:
+		http.Error(w, "Invalid format value, expected ndjson, csv or json", http.StatusBadRequest)
+		return
+	}
+
+	filter, err := parseSongFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", writer.contentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="songs.%s"`, format))
+
+	// The export outlives the server write timeout, the deadline is pushed forward as rows go out
+	rc := http.NewResponseController(w)
+	extendDeadline := func() {
+		if err := rc.SetWriteDeadline(time.Now().Add(exportWriteTimeout)); err != nil {
+			h.logger.Debug("Failed to extend write deadline", "error", err)
+		}
+	}
+	extendDeadline()
+
+	exported := 0
+	err = h.songRepo.Export(r.Context(), filter, func(s models.Song) error {
+		if exported == 0 {
+			if err := writer.begin(); err != nil {
+				return err
+			}
+		}
+
+		if err := writer.write(s); err != nil {
+			return err
+		}
+
+		exported++
+		if exported%exportFlushEvery == 0 {
+			if err := writer.flush(); err != nil {
+				return err
+			}
+			rc.Flush()
+			extendDeadline()
+		}
+		return nil
+	})
+	if err == nil && exported == 0 {
+		err = writer.begin()
+	}
+	if err == nil {
+		err = writer.end()
+	}
+	if err != nil {
+		// Once rows went out the status can't change anymore, the truncated body tells the client
+		if exported == 0 {
+			h.logger.Error("Error exporting songs", err)
+			w.Header().Del("Content-Disposition")
+			http.Error(w, "Error exporting songs", http.StatusInternalServerError)
+			return
+		}
+		h.logger.Error("Export aborted", "error", err, "exported", exported)
+		return
+	}
+
+	h.logger.Debug("Successfully exported songs", "count", exported)
+}
+
+// parseSongFilter reads the songs list filters from the query string
+func parseSongFilter(r *http.Request) (models.SongFilter, error) {
+	query := r.URL.Query()
+
+	filter := models.SongFilter{
+		Group:         strings.TrimSpace(query.Get("group")),
+		GroupContains: query.Get("group_contains"),
+		SongContains:  query.Get("song_contains"),
+		TextContains:  query.Get("text_contains"),
+	}
+
+	// Partial dates cover their whole period, released_to=2006 includes December 31st
+	var err error
+	if filter.ReleasedFrom, err = models.ParseReleaseDate(query.Get("released_from")); err != nil {
+		return filter, errors.New("Invalid released_from value")
+	}
+	if filter.ReleasedTo, err = models.ParseReleaseDate(query.Get("released_to")); err != nil {
+		return filter, errors.New("Invalid released_to value")
+	}
+
+	if hasLink := query.Get("has_link"); hasLink != "" {
+		value, err := strconv.ParseBool(hasLink)
+		if err != nil {
+			return filter, errors.New("Invalid has_link value")
+		}
+		filter.HasLink = &value
+	}
+
+	filter.Tags = parseTagNames(query["tag"])
+	filter.TagsAny = parseTagNames(query["tag_any"])
+	filter.TagsNone = parseTagNames(query["tag_none"])
+
+	return filter, nil
+}
+
+// parseTagNames collects normalized tag names from repeated or comma-separated parameters
+func parseTagNames(values []string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = models.NormalizeTagName(name)
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// @Summary     Search songs
+// @Description Full-text search over song titles, groups and lyrics in Russian and English.
+// @Description Results are ranked, carry a highlighted snippet and the index of the first
+// @Description matching verse, usable as offset for /songs/text.
+// @Tags        songs
+// @Accept      json
+// @Produce     json
+// @Param       q query string true "Search query, supports quotes, OR and -exclusions"
+// @Param       limit query int false "Number of items to return (default: 10)"
+// @Param       offset query int false "Number of items to skip (default: 0)"
+// @Success     200 {array} models.SongSearchResult
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     500 {object} models.ErrorResponse
+// @Failure     429 {object} models.RateLimitResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/search [get]
+func (h *songHandlers) Search(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		http.Error(w, "Search query is required", http.StatusBadRequest)
+		return
+	}
+
+	limit := r.URL.Query().Get("limit")
+	offset := r.URL.Query().Get("offset")
+
+	if limit == "" {
+		limit = defaultLimit
+	}
+	if offset == "" {
+		offset = defaultOffset
+	}
+
+	limitInt, err := strconv.Atoi(limit)
+	if err != nil || limitInt <= 0 {
+		http.Error(w, "Invalid limit value", http.StatusBadRequest)
+		return
+	}
+	offsetInt, err := strconv.Atoi(offset)
+	if err != nil || offsetInt < 0 {
+		http.Error(w, "Invalid offset value", http.StatusBadRequest)
+		return
+	}
+
+	results, err := h.songRepo.Search(r.Context(), query, searchConfig(query), limitInt, offsetInt)
+	if err != nil {
+		h.logger.Error("Error searching songs", err)
+		http.Error(w, "Error searching songs", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(results); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
+
+// searchConfig picks the text search configuration for highlighting, queries
+// with Cyrillic letters are highlighted with Russian stemming
+func searchConfig(query string) string {
+	for _, r := range query {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return "russian"
+		}
+	}
+	return "english"
+}
+
+// @Summary     Get song
+// @Description Get a song by ID
 // @Tags        songs
 // @Accept      json
 // @Produce     json
-// @Param       id query int true "Song ID"
-// @Param       limit query int false "Number of verses to return (default: 10)"
-// @Param       offset query int false "Number of verses to skip (default: 0)"
+// @Param       id path int true "Song ID"
 // @Success     200 {object} models.Song
 // @Failure     400 {object} models.ErrorResponse
 // @Failure     404 {object} models.ErrorResponse
-// @Router      /songs/text [get]
-func (h *songHandlers) GetText(w http.ResponseWriter, r *http.Request) {
-	// Get song ID from query parameters
-	id := r.URL.Query().Get("id")
-	if id == "" {
-		http.Error(w, "Song ID is required", http.StatusBadRequest)
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/{id} [get]
+func (h *songHandlers) GetByID(w http.ResponseWriter, r *http.Request) {
+	songID, ok := parseSongID(w, r)
+	if !ok {
 		return
 	}
 
-	// Convert ID to integer
-	songID, err := strconv.Atoi(id)
+	result, err := h.songRepo.GetByID(r.Context(), songID)
 	if err != nil {
-		http.Error(w, "Invalid song ID", http.StatusBadRequest)
+		if errors.Is(err, song.ErrNotFound) {
+			http.Error(w, "Song not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Error getting song", err)
+		http.Error(w, "Error getting song", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
+
+// @Summary     Get song text
+// @Description Get the text of a song by ID with pagination by line or by stanza.
+// @Description Lines and stanzas carry the stanza index and type (verse, chorus, bridge...).
+// @Tags        songs
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Song ID"
+// @Param       unit query string false "Pagination unit: line or stanza (default: line)"
+// @Param       limit query int false "Number of lines or stanzas to return (default: 10)"
+// @Param       offset query int false "Number of lines or stanzas to skip (default: 0)"
+// @Success     200 {object} models.SongTextResponse
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/{id}/lyrics [get]
+func (h *songHandlers) GetText(w http.ResponseWriter, r *http.Request) {
+	songID, ok := parseSongID(w, r)
+	if !ok {
 		return
 	}
 
 	// Get pagination parameters
+	unit := r.URL.Query().Get("unit")
 	limit := r.URL.Query().Get("limit")
 	offset := r.URL.Query().Get("offset")
 
+	if unit == "" {
+		unit = models.TextUnitLine
+	}
 	if limit == "" {
 		limit = defaultLimit
 	}
@@ -168,70 +536,78 @@ func (h *songHandlers) GetText(w http.ResponseWriter, r *http.Request) {
 		offset = defaultOffset
 	}
 
+	if unit != models.TextUnitLine && unit != models.TextUnitStanza {
+		http.Error(w, "Invalid unit value, expected line or stanza", http.StatusBadRequest)
+		return
+	}
+
 	// Convert pagination parameters to integers
 	limitInt, err := strconv.Atoi(limit)
-	if err != nil {
+	if err != nil || limitInt <= 0 {
 		http.Error(w, "Invalid limit value", http.StatusBadRequest)
 		return
 	}
 	offsetInt, err := strconv.Atoi(offset)
-	if err != nil {
+	if err != nil || offsetInt < 0 {
 		http.Error(w, "Invalid offset value", http.StatusBadRequest)
 		return
 	}
 
-	// Get song text from repository
-	text, err := h.songRepo.GetText(songID)
+	// Get song stanzas from repository
+	stanzas, err := h.songRepo.GetStanzas(r.Context(), songID)
 	if err != nil {
+		if errors.Is(err, song.ErrNotFound) {
+			http.Error(w, "Song not found", http.StatusNotFound)
+			return
+		}
 		h.logger.Error("Error getting song text", err)
-		http.Error(w, "Song not found", http.StatusNotFound)
+		http.Error(w, "Error getting song text", http.StatusInternalServerError)
 		return
 	}
 
-	if text == "" {
+	if len(stanzas) == 0 {
 		http.Error(w, "Song text is empty", http.StatusNotFound)
 		return
 	}
 
-	// Replace escaped newlines with actual newlines
-	text = strings.ReplaceAll(text, "\\n", "\n")
-
-	// Split the song text into lines
-	lines := strings.Split(text, "\n")
-
-	// Process lines to remove empty ones and verse markers
-	var verses []string
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			verses = append(verses, line)
+	// Build the items to paginate over, either every line or every stanza
+	var items []models.SongTextItem
+	for _, stanza := range stanzas {
+		if unit == models.TextUnitStanza {
+			items = append(items, models.SongTextItem{Stanza: stanza.Position, Type: stanza.Type, Text: stanza.Text})
+			continue
+		}
+		for _, line := range strings.Split(stanza.Text, "\n") {
+			items = append(items, models.SongTextItem{Stanza: stanza.Position, Type: stanza.Type, Text: line})
 		}
 	}
 
 	// Validate offset
-	if offsetInt >= len(verses) {
+	if offsetInt >= len(items) {
 		http.Error(w, "Offset is out of range", http.StatusBadRequest)
 		return
 	}
 
 	// Calculate end index for pagination
 	endIndex := offsetInt + limitInt
-	if endIndex > len(verses) {
-		endIndex = len(verses)
+	if endIndex > len(items) {
+		endIndex = len(items)
 	}
 
-	// Paginate the verses
-	paginatedVerses := verses[offsetInt:endIndex]
+	// Paginate the items
+	paginatedItems := items[offsetInt:endIndex]
+	verses := make([]string, len(paginatedItems))
+	for i, item := range paginatedItems {
+		verses[i] = item.Text
+	}
 
 	// Create response structure
-	response := struct {
-		Total   int      `json:"total"`
-		Verses  []string `json:"verses"`
-		HasMore bool     `json:"has_more"`
-	}{
-		Total:   len(verses),
-		Verses:  paginatedVerses,
-		HasMore: endIndex < len(verses),
+	response := models.SongTextResponse{
+		Unit:    unit,
+		Total:   len(items),
+		Verses:  verses,
+		Items:   paginatedItems,
+		HasMore: endIndex < len(items),
 	}
 
 	// Marshal the response to JSON
@@ -249,35 +625,46 @@ func (h *songHandlers) GetText(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary     Delete song
-// @Description Delete a song by ID
+// @Description Move a song to the trash, or remove it permanently with hard=true
 // @Tags        songs
 // @Accept      json
 // @Produce     plain
-// @Param       id query int true "Song ID"
+// @Param       id path int true "Song ID"
+// @Param       hard query bool false "Delete permanently instead of moving to the trash"
 // @Success     200 {string} string "Song deleted successfully"
 // @Failure     400 {object} models.ErrorResponse
 // @Failure     404 {object} models.ErrorResponse
-// @Router      /songs/ [delete]
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/{id} [delete]
 func (h *songHandlers) Delete(w http.ResponseWriter, r *http.Request) {
-	// Get the song ID from the URL parameters
-	id := r.URL.Query().Get("id")
-
-	// Validate the song ID
-	if id == "" {
-		http.Error(w, "Song ID is required", http.StatusBadRequest)
+	songID, ok := parseSongID(w, r)
+	if !ok {
 		return
 	}
 
-	// Convert the song ID to an integer
-	songID, err := strconv.Atoi(id)
-	if err != nil {
-		http.Error(w, "Invalid song ID", http.StatusBadRequest)
-		return
+	var err error
+	hard := false
+	if value := r.URL.Query().Get("hard"); value != "" {
+		hard, err = strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "Invalid hard value", http.StatusBadRequest)
+			return
+		}
 	}
 
-	// Delete the song from the repository
-	err = h.songRepo.Delete(songID)
+	// Songs go to the trash unless a permanent delete was asked for explicitly
+	if hard {
+		err = h.songRepo.HardDelete(r.Context(), songID)
+	} else {
+		err = h.songRepo.Delete(r.Context(), songID)
+	}
 	if err != nil {
+		if errors.Is(err, song.ErrNotFound) {
+			http.Error(w, "Song not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Error deleting song", err)
 		http.Error(w, "Error deleting song", http.StatusInternalServerError)
 		return
 	}
@@ -287,38 +674,329 @@ func (h *songHandlers) Delete(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("Song deleted successfully"))
 }
 
-// @Summary     Update song
-// @Description Update song details by ID
+// @Summary     Get deleted songs
+// @Description Get songs in the trash, most recently deleted first
 // @Tags        songs
 // @Accept      json
 // @Produce     json
-// @Param       id query int true "Song ID"
-// @Param       request body models.UpdateSongRequest true "Song update request"
-// @Success     200 {object} models.Song
+// @Param       limit query int false "Number of items to return (default: 10)"
+// @Param       offset query int false "Number of items to skip (default: 0)"
+// @Success     200 {array} models.Song
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     500 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/trash [get]
+func (h *songHandlers) GetTrash(w http.ResponseWriter, r *http.Request) {
+	limit := r.URL.Query().Get("limit")
+	offset := r.URL.Query().Get("offset")
+
+	if limit == "" {
+		limit = defaultLimit
+	}
+	if offset == "" {
+		offset = defaultOffset
+	}
+
+	limitInt, err := strconv.Atoi(limit)
+	if err != nil || limitInt <= 0 {
+		http.Error(w, "Invalid limit value", http.StatusBadRequest)
+		return
+	}
+	offsetInt, err := strconv.Atoi(offset)
+	if err != nil || offsetInt < 0 {
+		http.Error(w, "Invalid offset value", http.StatusBadRequest)
+		return
+	}
+
+	songs, err := h.songRepo.GetTrash(r.Context(), limitInt, offsetInt)
+	if err != nil {
+		h.logger.Error("Error getting deleted songs", err)
+		http.Error(w, "Error getting deleted songs", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(songs); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
+
+// @Summary     Restore song
+// @Description Bring a song back from the trash
+// @Tags        songs
+// @Accept      json
+// @Produce     plain
+// @Param       id path int true "Song ID"
+// @Success     200 {string} string "Song restored successfully"
 // @Failure     400 {object} models.ErrorResponse
 // @Failure     404 {object} models.ErrorResponse
-// @Router      /songs/ [put]
-func (h *songHandlers) Update(w http.ResponseWriter, r *http.Request) {
-	// Get the song ID from the URL parameters
-	id := r.URL.Query().Get("id")
+// @Failure     409 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/{id}/restore [post]
+func (h *songHandlers) Restore(w http.ResponseWriter, r *http.Request) {
+	songID, ok := parseSongID(w, r)
+	if !ok {
+		return
+	}
 
-	// Validate the song ID
-	if id == "" {
-		http.Error(w, "Song ID is required", http.StatusBadRequest)
+	err := h.songRepo.Restore(r.Context(), songID)
+	if err != nil {
+		switch {
+		case errors.Is(err, song.ErrNotFound):
+			http.Error(w, "Song not found in trash", http.StatusNotFound)
+		case errors.Is(err, song.ErrTrackPositionTaken), errors.Is(err, song.ErrDuplicateSong):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			h.logger.Error("Error restoring song", err)
+			http.Error(w, "Error restoring song", http.StatusInternalServerError)
+		}
 		return
 	}
 
-	// Convert the song ID to an integer
-	songID, err := strconv.Atoi(id)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Song restored successfully"))
+}
+
+// @Summary     Retry song enrichment
+// @Description Queue a song whose enrichment failed for another round of music API lookups
+// @Tags        songs
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Song ID"
+// @Success     202 {object} models.Song
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Failure     409 {object} models.ErrorResponse "Enrichment has not failed"
+// @Failure     429 {object} models.RateLimitResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/{id}/enrichment/retry [post]
+func (h *songHandlers) RetryEnrichment(w http.ResponseWriter, r *http.Request) {
+	songID, ok := parseSongID(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.songRepo.RetryEnrichment(r.Context(), songID)
 	if err != nil {
-		http.Error(w, "Invalid song ID", http.StatusBadRequest)
+		switch {
+		case errors.Is(err, song.ErrNotFound):
+			http.Error(w, "Song not found", http.StatusNotFound)
+		case errors.Is(err, song.ErrEnrichmentNotFailed):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			h.logger.Error("Error retrying song enrichment", err)
+			http.Error(w, "Error retrying song enrichment", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Location", songLocation(result.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
+
+// @Summary     Get song revisions
+// @Description Get the change history of a song, newest revision first
+// @Tags        songs
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Song ID"
+// @Param       limit query int false "Number of items to return (default: 10)"
+// @Param       offset query int false "Number of items to skip (default: 0)"
+// @Success     200 {array} models.SongRevision
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/{id}/revisions [get]
+func (h *songHandlers) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	songID, ok := parseSongID(w, r)
+	if !ok {
+		return
+	}
+
+	limit := r.URL.Query().Get("limit")
+	offset := r.URL.Query().Get("offset")
+
+	if limit == "" {
+		limit = defaultLimit
+	}
+	if offset == "" {
+		offset = defaultOffset
+	}
+
+	limitInt, err := strconv.Atoi(limit)
+	if err != nil || limitInt <= 0 {
+		http.Error(w, "Invalid limit value", http.StatusBadRequest)
+		return
+	}
+	offsetInt, err := strconv.Atoi(offset)
+	if err != nil || offsetInt < 0 {
+		http.Error(w, "Invalid offset value", http.StatusBadRequest)
+		return
+	}
+
+	revisions, err := h.songRepo.GetRevisions(r.Context(), songID, limitInt, offsetInt)
+	if err != nil {
+		h.logger.Error("Error getting song revisions", err)
+		http.Error(w, "Error getting song revisions", http.StatusInternalServerError)
+		return
+	}
+
+	// Every song has at least the revision written when it was created
+	if len(revisions) == 0 && offsetInt == 0 {
+		http.Error(w, "Song not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(revisions); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
+
+// @Summary     Diff song revisions
+// @Description Show which fields changed between two revisions, lyrics are compared line by line
+// @Tags        songs
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Song ID"
+// @Param       from query int true "Revision to compare from"
+// @Param       to query int true "Revision to compare to"
+// @Success     200 {object} models.SongRevisionDiff
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/{id}/revisions/diff [get]
+func (h *songHandlers) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	songID, ok := parseSongID(w, r)
+	if !ok {
+		return
+	}
+
+	fromRevision, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from revision", http.StatusBadRequest)
+		return
+	}
+	toRevision, err := strconv.Atoi(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "Invalid to revision", http.StatusBadRequest)
+		return
+	}
+
+	from, err := h.songRepo.GetRevision(r.Context(), songID, fromRevision)
+	if err != nil {
+		h.handleRevisionError(w, err, "Error getting song revision")
+		return
+	}
+	to, err := h.songRepo.GetRevision(r.Context(), songID, toRevision)
+	if err != nil {
+		h.handleRevisionError(w, err, "Error getting song revision")
+		return
+	}
+
+	fields, lines := models.DiffSongs(from.Snapshot, to.Snapshot)
+	diff := models.SongRevisionDiff{
+		SongID: songID,
+		From:   fromRevision,
+		To:     toRevision,
+		Fields: fields,
+		Lyrics: lines,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(diff); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
+
+// @Summary     Roll back song
+// @Description Restore the song fields from a previous revision, the rollback is recorded as a new revision
+// @Tags        songs
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Song ID"
+// @Param       revision path int true "Revision to roll back to"
+// @Success     200 {object} models.Song
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Failure     409 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/{id}/revisions/{revision}/rollback [post]
+func (h *songHandlers) Rollback(w http.ResponseWriter, r *http.Request) {
+	songID, ok := parseSongID(w, r)
+	if !ok {
+		return
+	}
+	revision, err := strconv.Atoi(mux.Vars(r)["revision"])
+	if err != nil {
+		http.Error(w, "Invalid revision", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.songRepo.Rollback(r.Context(), songID, revision)
+	if err != nil {
+		h.handleRevisionError(w, err, "Error rolling back song")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
+
+// handleRevisionError maps song revision errors to HTTP responses
+func (h *songHandlers) handleRevisionError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, song.ErrRevisionNotFound):
+		http.Error(w, "Revision not found", http.StatusNotFound)
+	case errors.Is(err, song.ErrNotFound):
+		http.Error(w, "Song not found", http.StatusNotFound)
+	case errors.Is(err, song.ErrRevisionConflict), errors.Is(err, song.ErrTrackPositionTaken), errors.Is(err, song.ErrDuplicateSong):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		h.logger.Error(message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+// @Summary     Update song
+// @Description Update song details by ID
+// @Tags        songs
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Song ID"
+// @Param       request body models.UpdateSongRequest true "Song update request"
+// @Success     200 {object} models.Song
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/{id} [put]
+func (h *songHandlers) Update(w http.ResponseWriter, r *http.Request) {
+	songID, ok := parseSongID(w, r)
+	if !ok {
 		return
 	}
 
 	// Get the updated song data from the request body
 	var song models.Song
-	err = json.NewDecoder(r.Body).Decode(&song)
+	err := json.NewDecoder(r.Body).Decode(&song)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidReleaseDate) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
@@ -326,18 +1004,36 @@ func (h *songHandlers) Update(w http.ResponseWriter, r *http.Request) {
 	// Set the ID from the URL
 	song.ID = songID
 
+	// Resolve the artist so the song references it instead of a raw name
+	song.ArtistID = 0
+	if strings.TrimSpace(song.Group) != "" {
+		songArtist, err := h.artistRepo.GetOrCreate(r.Context(), song.Group)
+		if err != nil {
+			h.logger.Error("Failed to resolve artist", err)
+			http.Error(w, "Error updating song", http.StatusInternalServerError)
+			return
+		}
+		song.ArtistID = songArtist.ID
+		song.Group = songArtist.Name
+	}
+
+	// Validate the album position, a song attached to an album needs a track number
+	if err := song.NormalizeAlbumPosition(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Update the song in the repository
-	err = h.songRepo.Update(&song)
+	err = h.songRepo.Update(r.Context(), &song)
 	if err != nil {
-		h.logger.Error("Failed to update song", err)
-		http.Error(w, fmt.Sprintf("Error updating song: %v", err), http.StatusInternalServerError)
+		h.handleUpdateError(w, err)
 		return
 	}
 
 	// Return a success response
 	response := map[string]string{
 		"message": "Song updated successfully",
-		"id":      id,
+		"id":      strconv.Itoa(songID),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -345,16 +1041,124 @@ func (h *songHandlers) Update(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// @Summary     Patch song
+// @Description Partially update a song with a JSON Merge Patch (RFC 7396): only the fields present change, null clears a field
+// @Tags        songs
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Song ID"
+// @Param       request body models.UpdateSongRequest true "Fields to change"
+// @Success     200 {object} models.Song
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Failure     409 {object} models.ErrorResponse
+// @Failure     415 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/{id} [patch]
+func (h *songHandlers) Patch(w http.ResponseWriter, r *http.Request) {
+	songID, ok := parseSongID(w, r)
+	if !ok {
+		return
+	}
+
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != mergePatchContentType && mediaType != "application/json") {
+			http.Error(w, "Content-Type must be "+mergePatchContentType, http.StatusUnsupportedMediaType)
+			return
+		}
+	}
+
+	var patch models.SongPatch
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patch); err != nil {
+		if errors.Is(err, models.ErrInvalidReleaseDate) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Invalid request body, expected a merge patch object", http.StatusBadRequest)
+		return
+	}
+	if err := patch.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Resolve the artist so the song references it instead of a raw name, null or an empty name clears it
+	if patch.Group.Set && !patch.Group.Null && strings.TrimSpace(patch.Group.Value) != "" {
+		songArtist, err := h.artistRepo.GetOrCreate(r.Context(), patch.Group.Value)
+		if err != nil {
+			h.logger.Error("Failed to resolve artist", err)
+			http.Error(w, "Error updating song", http.StatusInternalServerError)
+			return
+		}
+		patch.ArtistID = songArtist.ID
+		patch.Group.Value = songArtist.Name
+	} else if patch.Group.Set {
+		patch.Group = models.Optional[string]{Set: true, Null: true}
+	}
+
+	result, err := h.songRepo.Patch(r.Context(), songID, patch)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidAlbumPosition) || errors.Is(err, models.ErrSongTitleRequired) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.handleUpdateError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
+
+// handleUpdateError maps song update errors to HTTP responses
+func (h *songHandlers) handleUpdateError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, song.ErrNotFound):
+		http.Error(w, "Song not found", http.StatusNotFound)
+	case errors.Is(err, song.ErrArtistNotFound):
+		http.Error(w, "Artist not found", http.StatusBadRequest)
+	case errors.Is(err, song.ErrAlbumNotFound):
+		http.Error(w, "Album not found", http.StatusBadRequest)
+	case errors.Is(err, song.ErrTrackPositionTaken), errors.Is(err, song.ErrDuplicateSong):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		h.logger.Error("Failed to update song", err)
+		http.Error(w, fmt.Sprintf("Error updating song: %v", err), http.StatusInternalServerError)
+	}
+}
+
 // @Summary     Add new song
-// @Description Add a new song to the library
+// @Description Add a new song to the library. A song with the same artist and title (case and whitespace-insensitive)
+// @Description is rejected with 409 and its ID. Requests a caller retries with the same Idempotency-Key return the song
+// @Description created by the first one with 200 instead of creating it again.
+// @Description With "Prefer: respond-async" the song is stored without calling the music API and returned with 202
+// @Description and enrichmentStatus pending, background workers fill in the details later.
 // @Tags        songs
 // @Accept      json
 // @Produce     json
+// @Param       Idempotency-Key header string false "Unique key of the request, reusing it replays the first response"
+// @Param       Prefer header string false "respond-async to skip waiting for the music API"
 // @Param       request body models.AddSongRequest true "Song request"
+// @Success     200 {object} models.Song "Replayed request"
 // @Success     201 {object} models.Song
+// @Success     202 {object} models.Song "Stored, details are being fetched"
 // @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse "Song not found in the music API"
+// @Failure     409 {object} models.SongConflictResponse
+// @Failure     422 {object} models.ErrorResponse
 // @Failure     500 {object} models.ErrorResponse
-// @Router      /songs/ [post]
+// @Failure     502 {object} models.ErrorResponse "Invalid response from the music API"
+// @Failure     503 {object} models.ErrorResponse "Music API unavailable"
+// @Failure     429 {object} models.RateLimitResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs [post]
 func (h *songHandlers) Add(w http.ResponseWriter, r *http.Request) {
 	h.logger.Debug("Starting Add handler")
 
@@ -371,7 +1175,7 @@ func (h *songHandlers) Add(w http.ResponseWriter, r *http.Request) {
 	)
 
 	// Validate the request
-	if songRequest.Group == "" {
+	if strings.TrimSpace(songRequest.Group) == "" {
 		h.logger.Debug("Validation failed: empty Group")
 		http.Error(w, "Group is required", http.StatusBadRequest)
 		return
@@ -384,71 +1188,122 @@ func (h *songHandlers) Add(w http.ResponseWriter, r *http.Request) {
 
 	h.logger.Debug("Request validation passed")
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: time.Second * 10,
+	// A retried request gets the outcome of the first one instead of a second insert,
+	// keys are only matched against the earlier requests of the same caller
+	var caller string
+	if principal, ok := auth.PrincipalFrom(r.Context()); ok {
+		caller = principal.Key()
 	}
+	idempotencyKey := strings.TrimSpace(r.Header.Get(idempotencyKeyHeader))
+	if idempotencyKey != "" {
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			http.Error(w, "Idempotency-Key is too long", http.StatusBadRequest)
+			return
+		}
 
-	// Prepare request to external API
-	apiURL := fmt.Sprintf("%s?group=%s&song=%s",
-		h.cfg.MusicApi.URL,
-		url.QueryEscape(strings.ToLower(songRequest.Group)),
-		url.QueryEscape(strings.ToLower(songRequest.Song)),
-	)
+		record, reserved, err := h.songRepo.ReserveIdempotencyKey(r.Context(), caller, idempotencyKey, addRequestHash(songRequest))
+		if err != nil {
+			h.logger.Error("Failed to reserve idempotency key", "error", err)
+			http.Error(w, "Failed to create song", http.StatusInternalServerError)
+			return
+		}
+		if !reserved {
+			h.replayAdd(w, r, record, songRequest)
+			return
+		}
+	}
 
-	externalReq, err := http.NewRequestWithContext(r.Context(), "GET", apiURL, nil)
-	if err != nil {
-		h.logger.Error("Failed to create external API request", "error", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	async := prefersAsync(r)
+	createdSong := h.createSong(w, r, songRequest, async)
+
+	// The key is kept only when a song was created, otherwise the client may retry
+	if idempotencyKey != "" {
+		ctx := context.WithoutCancel(r.Context())
+		if createdSong == nil {
+			if err := h.songRepo.ReleaseIdempotencyKey(ctx, caller, idempotencyKey); err != nil {
+				h.logger.Error("Failed to release idempotency key", "error", err)
+			}
+		} else if err := h.songRepo.CompleteIdempotencyKey(ctx, caller, idempotencyKey, createdSong.ID); err != nil {
+			h.logger.Error("Failed to complete idempotency key", "error", err)
+		}
+	}
+	if createdSong == nil {
 		return
 	}
 
-	// Make request to external API
-	resp, err := client.Do(externalReq)
-	if err != nil {
-		h.logger.Error("Failed to make external API request", "error", err, "url", apiURL)
-		http.Error(w, "Failed to fetch song details", http.StatusInternalServerError)
+	// Return success response
+	status := http.StatusCreated
+	if async {
+		w.Header().Set("Preference-Applied", respondAsync)
+		status = http.StatusAccepted
+	}
+	w.Header().Set("Location", songLocation(createdSong.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(createdSong); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
 		return
 	}
-	defer resp.Body.Close()
-
-	h.logger.Debug("External API response received",
-		"status", resp.StatusCode,
-		"url", apiURL,
-	)
+}
 
-	// Read response body for logging
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		h.logger.Error("Failed to read response body", "error", err)
-		http.Error(w, "Failed to read song details", http.StatusInternalServerError)
-		return
+// createSong fetches the song details and stores the song, async stores it pending enrichment instead.
+// On failure the error response is already written and nil is returned
+func (h *songHandlers) createSong(w http.ResponseWriter, r *http.Request, songRequest models.AddSongRequest, async bool) *models.Song {
+	// Don't call the external API for a song that is already in the library
+	existing, err := h.songRepo.FindByTitle(r.Context(), songRequest.Group, songRequest.Song)
+	if err == nil {
+		writeSongConflict(w, existing.ID)
+		return nil
+	}
+	if !errors.Is(err, song.ErrNotFound) {
+		h.logger.Error("Failed to look up existing song", "error", err)
+		http.Error(w, "Failed to create song", http.StatusInternalServerError)
+		return nil
 	}
-	h.logger.Debug("External API response body", "body", string(bodyBytes))
 
-	// Create new reader from bytes for json.Decoder
-	resp.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+	newSong := &models.Song{
+		Song:             songRequest.Song,
+		EnrichmentStatus: models.EnrichmentPending,
+	}
+	if !async && !h.fillSongDetail(w, r, songRequest, newSong) {
+		return nil
+	}
 
-	// Check response status
-	if resp.StatusCode == http.StatusBadRequest {
-		http.Error(w, "Invalid song or group name", http.StatusBadRequest)
-		return
+	// Resolve or create the artist the song belongs to
+	songArtist, err := h.artistRepo.GetOrCreate(r.Context(), songRequest.Group)
+	if err != nil {
+		h.logger.Error("Failed to resolve artist", "error", err)
+		http.Error(w, "Failed to create song", http.StatusInternalServerError)
+		return nil
 	}
-	if resp.StatusCode != http.StatusOK {
-		h.logger.Error("External API returned non-200 status",
-			"status", resp.StatusCode,
-			"url", apiURL,
-		)
-		http.Error(w, "Failed to fetch song details", http.StatusInternalServerError)
-		return
+	newSong.ArtistID = songArtist.ID
+	newSong.Group = songArtist.Name
+
+	// Save to database
+	createdSong, err := h.songRepo.Create(r.Context(), newSong)
+	if err != nil {
+		// Another request may have added the same song while the details were fetched
+		if errors.Is(err, song.ErrDuplicateSong) {
+			if existing, err := h.songRepo.FindByTitle(r.Context(), songRequest.Group, songRequest.Song); err == nil {
+				writeSongConflict(w, existing.ID)
+				return nil
+			}
+		}
+		h.logger.Error("Failed to create song", "error", err)
+		http.Error(w, "Failed to create song", http.StatusInternalServerError)
+		return nil
 	}
 
-	// Parse external API response
-	var songDetail models.SongDetail
-	if err := json.NewDecoder(resp.Body).Decode(&songDetail); err != nil {
-		h.logger.Error("Failed to decode external API response", "error", err)
-		http.Error(w, "Failed to parse song details", http.StatusInternalServerError)
-		return
+	return createdSong
+}
+
+// fillSongDetail fetches the song details from the music API into newSong, on failure the error
+// response is already written and false is returned
+func (h *songHandlers) fillSongDetail(w http.ResponseWriter, r *http.Request, songRequest models.AddSongRequest, newSong *models.Song) bool {
+	songDetail, err := h.musicInfo.GetSongDetail(r.Context(), songRequest.Group, songRequest.Song)
+	if err != nil {
+		h.writeMusicInfoError(w, err)
+		return false
 	}
 
 	// Validate required fields from external API
@@ -458,32 +1313,123 @@ func (h *songHandlers) Add(w http.ResponseWriter, r *http.Request) {
 			"hasText", songDetail.Text != "",
 			"hasLink", songDetail.Link != "",
 		)
-		http.Error(w, "Incomplete song details received", http.StatusInternalServerError)
-		return
+		http.Error(w, "Incomplete song details received", http.StatusBadGateway)
+		return false
 	}
 
-	// Create song entity with combined data
-	song := &models.Song{
-		Group:       songRequest.Group,
-		Song:        songRequest.Song,
-		ReleaseDate: songDetail.ReleaseDate,
-		Text:        strings.ReplaceAll(songDetail.Text, "\n", "\\n"),
-		Link:        songDetail.Link,
+	releaseDate, err := models.ParseReleaseDate(songDetail.ReleaseDate)
+	if err != nil {
+		h.logger.Error("External API returned invalid release date",
+			"releaseDate", songDetail.ReleaseDate,
+			"error", err,
+		)
+		http.Error(w, "Invalid release date received", http.StatusBadGateway)
+		return false
 	}
 
-	// Save to database
-	createdSong, err := h.songRepo.Create(r.Context(), song)
+	newSong.ReleaseDate = releaseDate
+	newSong.Text = songDetail.Text
+	newSong.Link = songDetail.Link
+	newSong.EnrichmentStatus = models.EnrichmentEnriched
+	return true
+}
+
+// prefersAsync tells whether the client sent "Prefer: respond-async"
+func prefersAsync(r *http.Request) bool {
+	for _, value := range r.Header.Values("Prefer") {
+		for _, token := range strings.Split(value, ",") {
+			// Preferences may carry parameters after a semicolon
+			name, _, _ := strings.Cut(token, ";")
+			if strings.EqualFold(strings.TrimSpace(name), respondAsync) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeMusicInfoError answers a failed music API lookup: the API rejecting or not knowing the song is the
+// client's problem, the API being down or answering garbage is not
+func (h *songHandlers) writeMusicInfoError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, musicinfo.ErrInvalidRequest):
+		http.Error(w, "Invalid song or group name", http.StatusBadRequest)
+	case errors.Is(err, musicinfo.ErrNotFound):
+		http.Error(w, "Song not found in the music API", http.StatusNotFound)
+	case errors.Is(err, musicinfo.ErrBadResponse):
+		h.logger.Error("Music API returned an invalid response", "error", err)
+		http.Error(w, "Invalid song details received", http.StatusBadGateway)
+	default:
+		h.logger.Error("Failed to fetch song details", "error", err)
+		http.Error(w, "Music API is unavailable, try again later", http.StatusServiceUnavailable)
+	}
+}
+
+// replayAdd answers a request whose Idempotency-Key was already used
+func (h *songHandlers) replayAdd(w http.ResponseWriter, r *http.Request, record *models.IdempotencyRecord, songRequest models.AddSongRequest) {
+	if record.RequestHash != addRequestHash(songRequest) {
+		http.Error(w, "Idempotency-Key was already used with a different request", http.StatusUnprocessableEntity)
+		return
+	}
+	if !record.Completed() {
+		http.Error(w, "A request with this Idempotency-Key is still in progress", http.StatusConflict)
+		return
+	}
+
+	existing, err := h.songRepo.GetByID(r.Context(), record.SongID)
 	if err != nil {
-		h.logger.Error("Failed to create song", "error", err)
-		http.Error(w, "Failed to create song", http.StatusInternalServerError)
+		if errors.Is(err, song.ErrNotFound) {
+			http.Error(w, "Song not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get song", "error", err)
+		http.Error(w, "Failed to get song", http.StatusInternalServerError)
 		return
 	}
 
-	// Return success response
+	w.Header().Set("Content-Location", songLocation(existing.ID))
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(createdSong); err != nil {
+	if err := json.NewEncoder(w).Encode(existing); err != nil {
 		h.logger.Error("Failed to encode response", "error", err)
-		return
 	}
 }
+
+// addRequestHash fingerprints the request so a reused Idempotency-Key with another payload is detected
+func addRequestHash(req models.AddSongRequest) string {
+	sum := sha256.Sum256([]byte(models.NormalizeArtistName(req.Group) + "\x00" + models.NormalizeArtistName(req.Song)))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeSongConflict reports the song that is already in the library
+func writeSongConflict(w http.ResponseWriter, id int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(models.SongConflictResponse{
+		Message: song.ErrDuplicateSong.Error(),
+		ID:      id,
+	})
+}
+
+// parseSongID reads the song ID from the path, the deprecated routes pass it as the id query parameter
+func parseSongID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		id = r.URL.Query().Get("id")
+	}
+	if id == "" {
+		http.Error(w, "Song ID is required", http.StatusBadRequest)
+		return 0, false
+	}
+
+	songID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, "Invalid song ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return songID, true
+}
+
+// songLocation is the URL of the song resource
+func songLocation(id int) string {
+	return fmt.Sprintf("%s/%d", songsPath, id)
+}
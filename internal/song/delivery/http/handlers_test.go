@@ -0,0 +1,352 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"musiclib/config"
+	"musiclib/internal/artist"
+	"musiclib/internal/auth"
+	"musiclib/internal/models"
+	"musiclib/internal/song"
+	"musiclib/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// fakeSongRepo serves the songs in memory, methods a test does not need panic through the nil interface
+type fakeSongRepo struct {
+	song.Repository
+	songs     []models.Song
+	listQuery *models.SongListQuery
+	writeErr  error
+	// keys holds the idempotency records by caller and key
+	keys map[[2]string]*models.IdempotencyRecord
+}
+
+func (r *fakeSongRepo) GetList(ctx context.Context, q models.SongListQuery) ([]models.Song, error) {
+	r.listQuery = &q
+	songs := r.songs
+	if q.Offset < len(songs) {
+		songs = songs[q.Offset:]
+	} else {
+		songs = nil
+	}
+	if q.Limit < len(songs) {
+		songs = songs[:q.Limit]
+	}
+	return songs, nil
+}
+
+func (r *fakeSongRepo) GetTrash(ctx context.Context, limit int, offset int) ([]models.Song, error) {
+	return nil, nil
+}
+
+func (r *fakeSongRepo) Search(ctx context.Context, query string, headlineConfig string, limit int, offset int) ([]models.SongSearchResult, error) {
+	return nil, nil
+}
+
+func (r *fakeSongRepo) GetRevisions(ctx context.Context, songID int, limit int, offset int) ([]models.SongRevision, error) {
+	return []models.SongRevision{{SongID: songID, Revision: 1}}, nil
+}
+
+func (r *fakeSongRepo) Patch(ctx context.Context, id int, patch models.SongPatch) (*models.Song, error) {
+	if r.writeErr != nil {
+		return nil, r.writeErr
+	}
+	for _, s := range r.songs {
+		if s.ID != id {
+			continue
+		}
+		patch.ApplyTo(&s)
+		if err := s.NormalizeAlbumPosition(); err != nil {
+			return nil, err
+		}
+		return &s, nil
+	}
+	return nil, song.ErrNotFound
+}
+
+func (r *fakeSongRepo) GetByID(ctx context.Context, id int) (*models.Song, error) {
+	for _, s := range r.songs {
+		if s.ID == id {
+			return &s, nil
+		}
+	}
+	return nil, song.ErrNotFound
+}
+
+func (r *fakeSongRepo) FindByTitle(ctx context.Context, group string, title string) (*models.Song, error) {
+	for _, s := range r.songs {
+		if s.Group == group && s.Song == title {
+			return &s, nil
+		}
+	}
+	return nil, song.ErrNotFound
+}
+
+func (r *fakeSongRepo) Create(ctx context.Context, s *models.Song) (*models.Song, error) {
+	created := *s
+	created.ID = len(r.songs) + 1
+	r.songs = append(r.songs, created)
+	return &created, nil
+}
+
+func (r *fakeSongRepo) ReserveIdempotencyKey(ctx context.Context, principal string, key string, requestHash string) (*models.IdempotencyRecord, bool, error) {
+	if record, ok := r.keys[[2]string{principal, key}]; ok {
+		return record, false, nil
+	}
+	if r.keys == nil {
+		r.keys = make(map[[2]string]*models.IdempotencyRecord)
+	}
+	r.keys[[2]string{principal, key}] = &models.IdempotencyRecord{Principal: principal, Key: key, RequestHash: requestHash}
+	return nil, true, nil
+}
+
+func (r *fakeSongRepo) CompleteIdempotencyKey(ctx context.Context, principal string, key string, songID int) error {
+	r.keys[[2]string{principal, key}].SongID = songID
+	return nil
+}
+
+func (r *fakeSongRepo) ReleaseIdempotencyKey(ctx context.Context, principal string, key string) error {
+	delete(r.keys, [2]string{principal, key})
+	return nil
+}
+
+// fakeArtistRepo resolves every name to the same artist
+type fakeArtistRepo struct {
+	artist.Repository
+}
+
+func (r *fakeArtistRepo) GetOrCreate(ctx context.Context, name string) (*models.Artist, error) {
+	return &models.Artist{ID: 1, Name: name}, nil
+}
+
+func newTestRouter(repo song.Repository) *mux.Router {
+	cfg := &config.Config{}
+	cfg.Logger.Level = "fatal"
+	appLogger := logger.NewApiLogger(cfg)
+	appLogger.InitLogger()
+
+	router := mux.NewRouter()
+	MapSongRoutes(router.PathPrefix(songsPath).Subrouter(), NewSongHandlers(cfg, appLogger, repo, &fakeArtistRepo{}, nil))
+	return router
+}
+
+func serve(router http.Handler, method string, target string, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPaginationParameters(t *testing.T) {
+	tamperedCursor := base64.RawURLEncoding.EncodeToString([]byte(`{"s":"release_date","o":"asc","v":"never","i":1}`))
+
+	tests := []struct {
+		name   string
+		target string
+		want   int
+	}{
+		{"list defaults", "/songs", http.StatusOK},
+		{"list limit", "/songs?limit=2&offset=1", http.StatusOK},
+		{"list zero limit", "/songs?limit=0", http.StatusBadRequest},
+		{"list negative limit", "/songs?limit=-1", http.StatusBadRequest},
+		{"list negative limit with cursor", "/songs?limit=-1&cursor=", http.StatusBadRequest},
+		{"list negative offset", "/songs?offset=-1", http.StatusBadRequest},
+		{"list limit not a number", "/songs?limit=ten", http.StatusBadRequest},
+		{"list tampered cursor", "/songs?cursor=" + tamperedCursor, http.StatusBadRequest},
+		{"trash zero limit", "/songs/trash?limit=0", http.StatusBadRequest},
+		{"trash negative offset", "/songs/trash?offset=-5", http.StatusBadRequest},
+		{"trash", "/songs/trash?limit=5", http.StatusOK},
+		{"search negative limit", "/songs/search?q=love&limit=-1", http.StatusBadRequest},
+		{"search negative offset", "/songs/search?q=love&offset=-1", http.StatusBadRequest},
+		{"search", "/songs/search?q=love", http.StatusOK},
+		{"revisions zero limit", "/songs/1/revisions?limit=0", http.StatusBadRequest},
+		{"revisions negative offset", "/songs/1/revisions?offset=-1", http.StatusBadRequest},
+		{"revisions", "/songs/1/revisions", http.StatusOK},
+	}
+
+	router := newTestRouter(&fakeSongRepo{songs: []models.Song{{ID: 1}, {ID: 2}, {ID: 3}}})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(router, http.MethodGet, "/api/v1"+tt.target, "", nil)
+			if rec.Code != tt.want {
+				t.Errorf("GET %s = %d %q, want %d", tt.target, rec.Code, rec.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGetListCursorPages(t *testing.T) {
+	repo := &fakeSongRepo{songs: []models.Song{{ID: 1}, {ID: 2}, {ID: 3}}}
+	router := newTestRouter(repo)
+
+	rec := serve(router, http.MethodGet, songsPath+"?limit=2&cursor=", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET first page = %d %q, want 200", rec.Code, rec.Body.String())
+	}
+	if repo.listQuery.Limit != 3 {
+		t.Errorf("repository limit = %d, want one extra row", repo.listQuery.Limit)
+	}
+
+	var page models.SongPage
+	if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+		t.Fatalf("decode page: %v", err)
+	}
+	if len(page.Songs) != 2 || page.NextCursor == "" {
+		t.Fatalf("first page = %d songs, next cursor %q, want 2 songs and a cursor", len(page.Songs), page.NextCursor)
+	}
+
+	rec = serve(router, http.MethodGet, songsPath+"?limit=2&cursor="+page.NextCursor, "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET second page = %d %q, want 200", rec.Code, rec.Body.String())
+	}
+	if after := repo.listQuery.After; after == nil || after.ID != 2 {
+		t.Errorf("repository cursor = %+v, want after song 2", after)
+	}
+
+	rec = serve(router, http.MethodGet, songsPath+"?sort_by=song&cursor="+page.NextCursor, "", nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("GET with a different sort_by = %d, want 400", rec.Code)
+	}
+}
+
+func TestPatchRepositoryErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+		body string
+	}{
+		{song.ErrArtistNotFound, http.StatusBadRequest, "Artist not found\n"},
+		{song.ErrAlbumNotFound, http.StatusBadRequest, "Album not found\n"},
+		{song.ErrDuplicateSong, http.StatusConflict, song.ErrDuplicateSong.Error() + "\n"},
+		{song.ErrTrackPositionTaken, http.StatusConflict, song.ErrTrackPositionTaken.Error() + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			router := newTestRouter(&fakeSongRepo{writeErr: tt.err})
+			rec := serve(router, http.MethodPatch, songsPath+"/1", `{"albumId": 3, "trackNumber": 1}`, nil)
+			if rec.Code != tt.want || rec.Body.String() != tt.body {
+				t.Errorf("PATCH with %v = %d %q, want %d %q", tt.err, rec.Code, rec.Body.String(), tt.want, tt.body)
+			}
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	mergePatch := http.Header{"Content-Type": {mergePatchContentType}}
+
+	tests := []struct {
+		name     string
+		target   string
+		body     string
+		header   http.Header
+		want     int
+		wantSong string
+	}{
+		{"title", "/songs/1", `{"song": "Help!"}`, mergePatch, http.StatusOK, "Help!"},
+		{"plain json", "/songs/1", `{"link": null}`, http.Header{"Content-Type": {"application/json"}}, http.StatusOK, "Yesterday"},
+		{"null title", "/songs/1", `{"song": null}`, mergePatch, http.StatusBadRequest, ""},
+		{"empty title", "/songs/1", `{"song": ""}`, mergePatch, http.StatusBadRequest, ""},
+		{"blank title", "/songs/1", `{"song": "   "}`, mergePatch, http.StatusBadRequest, ""},
+		{"unknown field", "/songs/1", `{"title": "Help!"}`, mergePatch, http.StatusBadRequest, ""},
+		{"invalid release date", "/songs/1", `{"releaseDate": "someday"}`, mergePatch, http.StatusBadRequest, ""},
+		{"album without track", "/songs/1", `{"albumId": 3}`, mergePatch, http.StatusBadRequest, ""},
+		{"not a merge patch", "/songs/1", `{"song": "Help!"}`, http.Header{"Content-Type": {"text/plain"}}, http.StatusUnsupportedMediaType, ""},
+		{"missing song", "/songs/2", `{"song": "Help!"}`, mergePatch, http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(&fakeSongRepo{songs: []models.Song{{ID: 1, Song: "Yesterday", Link: "https://example.com"}}})
+			rec := serve(router, http.MethodPatch, "/api/v1"+tt.target, tt.body, tt.header)
+			if rec.Code != tt.want {
+				t.Fatalf("PATCH %s %s = %d %q, want %d", tt.target, tt.body, rec.Code, rec.Body.String(), tt.want)
+			}
+			if tt.wantSong == "" {
+				return
+			}
+
+			var got models.Song
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("decode song: %v", err)
+			}
+			if got.Song != tt.wantSong {
+				t.Errorf("patched title = %q, want %q", got.Song, tt.wantSong)
+			}
+		})
+	}
+}
+
+func TestAddIdempotency(t *testing.T) {
+	repo := &fakeSongRepo{}
+	router := newTestRouter(repo)
+	// as signs the requests in as the user, idempotency keys are scoped to the caller
+	as := func(userID int) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			router.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), models.Principal{UserID: userID, Role: models.RoleEditor})))
+		})
+	}
+	header := func(key string) http.Header {
+		return http.Header{"Idempotency-Key": {key}, "Prefer": {respondAsync}}
+	}
+	const body = `{"group": "The Beatles", "song": "Yesterday"}`
+
+	rec := serve(as(1), http.MethodPost, songsPath, body, header("retry-1"))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("first POST = %d %q, want 202", rec.Code, rec.Body.String())
+	}
+
+	tests := []struct {
+		name   string
+		user   int
+		key    string
+		body   string
+		want   int
+		wantID int
+	}{
+		{"retry replays the song", 1, "retry-1", body, http.StatusOK, 1},
+		{"retry with other spacing and case", 1, "retry-1", `{"group": "the  beatles", "song": "YESTERDAY"}`, http.StatusOK, 1},
+		{"key reused for another song", 1, "retry-1", `{"group": "The Beatles", "song": "Help!"}`, http.StatusUnprocessableEntity, 0},
+		{"same key from another caller", 2, "retry-1", body, http.StatusConflict, 1},
+		{"key too long", 1, strings.Repeat("k", maxIdempotencyKeyLength+1), body, http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(as(tt.user), http.MethodPost, songsPath, tt.body, header(tt.key))
+			if rec.Code != tt.want {
+				t.Fatalf("POST = %d %q, want %d", rec.Code, rec.Body.String(), tt.want)
+			}
+			if tt.wantID == 0 {
+				return
+			}
+
+			var got struct {
+				ID int `json:"id"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if got.ID != tt.wantID {
+				t.Errorf("song ID = %d, want %d", got.ID, tt.wantID)
+			}
+		})
+	}
+
+	if len(repo.songs) != 1 {
+		t.Errorf("stored %d songs, want 1", len(repo.songs))
+	}
+	if _, ok := repo.keys[[2]string{"user:2", "retry-1"}]; ok {
+		t.Error("key of the rejected duplicate was kept, the caller could not retry")
+	}
+}
@@ -1,16 +1,73 @@
 package http
 
 import (
+	"fmt"
 	"musiclib/internal/song"
+	"net/http"
+	"net/url"
 
 	"github.com/gorilla/mux"
 )
 
+// Route names, rate limits are configured per route name
+const (
+	// RouteAdd looks the song up in the music API on every call
+	RouteAdd    = "songs_add"
+	RouteSearch = "songs_search"
+	RouteExport = "songs_export"
+)
+
 // Map songs routes
 func MapSongRoutes(newsGroup *mux.Router, h song.Handlers) {
-	newsGroup.HandleFunc("/list", h.GetList).Methods("GET")
-	newsGroup.HandleFunc("/text", h.GetText).Methods("GET")
-	newsGroup.HandleFunc("/", h.Delete).Methods("DELETE")
-	newsGroup.HandleFunc("/", h.Update).Methods("PUT")
-	newsGroup.HandleFunc("/", h.Add).Methods("POST")
+	newsGroup.HandleFunc("", h.GetList).Methods("GET")
+	newsGroup.HandleFunc("", h.Add).Methods("POST").Name(RouteAdd)
+	newsGroup.HandleFunc("/search", h.Search).Methods("GET").Name(RouteSearch)
+	newsGroup.HandleFunc("/export", h.Export).Methods("GET").Name(RouteExport)
+	newsGroup.HandleFunc("/trash", h.GetTrash).Methods("GET")
+	newsGroup.HandleFunc("/{id:[0-9]+}", h.GetByID).Methods("GET")
+	newsGroup.HandleFunc("/{id:[0-9]+}", h.Update).Methods("PUT")
+	newsGroup.HandleFunc("/{id:[0-9]+}", h.Patch).Methods("PATCH")
+	newsGroup.HandleFunc("/{id:[0-9]+}", h.Delete).Methods("DELETE")
+	newsGroup.HandleFunc("/{id:[0-9]+}/lyrics", h.GetText).Methods("GET")
+	newsGroup.HandleFunc("/{id:[0-9]+}/restore", h.Restore).Methods("POST")
+	newsGroup.HandleFunc("/{id:[0-9]+}/enrichment/retry", h.RetryEnrichment).Methods("POST")
+	newsGroup.HandleFunc("/{id:[0-9]+}/revisions", h.GetRevisions).Methods("GET")
+	newsGroup.HandleFunc("/{id:[0-9]+}/revisions/diff", h.DiffRevisions).Methods("GET")
+	newsGroup.HandleFunc("/{id:[0-9]+}/revisions/{revision:[0-9]+}/rollback", h.Rollback).Methods("POST")
+
+	// Routes from before the songs became a resource, kept for existing clients
+	newsGroup.HandleFunc("/list", deprecated(h.GetList, collectionSuccessor)).Methods("GET")
+	newsGroup.HandleFunc("/text", deprecated(h.GetText, lyricsSuccessor)).Methods("GET")
+	newsGroup.HandleFunc("/", deprecated(h.Delete, songSuccessor)).Methods("DELETE")
+	newsGroup.HandleFunc("/", deprecated(h.Update, songSuccessor)).Methods("PUT")
+	newsGroup.HandleFunc("/", deprecated(h.Add, collectionSuccessor)).Methods("POST").Name(RouteAdd)
+}
+
+// deprecated marks responses of an old route with the Deprecation header and links the route replacing it
+func deprecated(next http.HandlerFunc, successor func(r *http.Request) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor(r)))
+		next(w, r)
+	}
+}
+
+func collectionSuccessor(r *http.Request) string {
+	return songsPath
+}
+
+func songSuccessor(r *http.Request) string {
+	return songsPath + "/" + urlID(r)
+}
+
+func lyricsSuccessor(r *http.Request) string {
+	return songsPath + "/" + urlID(r) + "/lyrics"
+}
+
+// urlID is the id query parameter of an old route, or a placeholder when it is missing
+func urlID(r *http.Request) string {
+	if id := r.URL.Query().Get("id"); id != "" {
+		return url.PathEscape(id)
+	}
+	return "{id}"
 }
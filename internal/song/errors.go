@@ -0,0 +1,15 @@
+package song
+
+import "errors"
+
+var (
+	ErrNotFound            = errors.New("song not found")
+	ErrArtistNotFound      = errors.New("artist not found")
+	ErrAlbumNotFound       = errors.New("album not found")
+	ErrTrackPositionTaken  = errors.New("track position is already taken on this album")
+	ErrDuplicateSong       = errors.New("song with this title already exists for the artist")
+	ErrRevisionNotFound    = errors.New("song revision not found")
+	ErrRevisionConflict    = errors.New("revision refers to an artist or album that no longer exists")
+	ErrEnrichmentNotFailed = errors.New("song enrichment has not failed")
+	ErrNoEnrichmentJobs    = errors.New("no enrichment jobs are due")
+)
@@ -3,13 +3,36 @@ package song
 import (
 	"context"
 	"musiclib/internal/models"
+	"time"
 )
 
 // Repository interface
 type Repository interface {
-	GetList(sortBy string, sortOrder string, limit int, offset int) ([]models.Song, error)
-	GetText(id int) (string, error)
-	Delete(id int) error
-	Update(song *models.Song) error
+	GetList(ctx context.Context, query models.SongListQuery) ([]models.Song, error)
+	Search(ctx context.Context, query string, headlineConfig string, limit int, offset int) ([]models.SongSearchResult, error)
+	GetStanzas(ctx context.Context, id int) ([]models.Stanza, error)
+	ResplitStanzas(ctx context.Context, limit int) (int, error)
+	Export(ctx context.Context, filter models.SongFilter, fn func(models.Song) error) error
+	GetTrash(ctx context.Context, limit int, offset int) ([]models.Song, error)
+	Delete(ctx context.Context, id int) error
+	HardDelete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	Update(ctx context.Context, song *models.Song) error
+	Patch(ctx context.Context, id int, patch models.SongPatch) (*models.Song, error)
+	GetByID(ctx context.Context, id int) (*models.Song, error)
+	FindByTitle(ctx context.Context, group string, title string) (*models.Song, error)
+	ReserveIdempotencyKey(ctx context.Context, principal string, key string, requestHash string) (*models.IdempotencyRecord, bool, error)
+	CompleteIdempotencyKey(ctx context.Context, principal string, key string, songID int) error
+	ReleaseIdempotencyKey(ctx context.Context, principal string, key string) error
+	PurgeIdempotencyKeys(ctx context.Context) (int64, error)
 	Create(ctx context.Context, song *models.Song) (*models.Song, error)
+	GetRevisions(ctx context.Context, songID int, limit int, offset int) ([]models.SongRevision, error)
+	GetRevision(ctx context.Context, songID int, revision int) (*models.SongRevision, error)
+	Rollback(ctx context.Context, songID int, revision int) (*models.Song, error)
+	ClaimEnrichmentJob(ctx context.Context, lease time.Duration) (*models.EnrichmentJob, error)
+	CompleteEnrichment(ctx context.Context, songID int, detail *models.Song) (*models.Song, error)
+	RescheduleEnrichment(ctx context.Context, songID int, reason string, at time.Time) error
+	FailEnrichment(ctx context.Context, songID int, reason string) error
+	RetryEnrichment(ctx context.Context, songID int) (*models.Song, error)
 }
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"fmt"
+	"musiclib/internal/models"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// sortColumns maps the public sort fields to SQL expressions, they match
+// models.Song.SortKey so a cursor can be built from the last row
+var sortColumns = map[string]string{
+	"id":           "s.id",
+	"group_name":   "COALESCE(a.name, '')",
+	"song":         "COALESCE(s.song, '')",
+	"release_date": "COALESCE(s.release_date, DATE '0001-01-01')",
+}
+
+// sortCasts holds the casts applied to cursor values of non-text sort fields
+var sortCasts = map[string]string{
+	"release_date": "::date",
+}
+
+// whereBuilder collects conditions with numbered placeholders and their arguments
+type whereBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// arg registers a bound parameter and returns its placeholder
+func (b *whereBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// add appends a condition, every %s in format is replaced by a placeholder for the matching value
+func (b *whereBuilder) add(format string, values ...interface{}) {
+	placeholders := make([]interface{}, len(values))
+	for i, v := range values {
+		placeholders[i] = b.arg(v)
+	}
+	b.conditions = append(b.conditions, fmt.Sprintf(format, placeholders...))
+}
+
+func (b *whereBuilder) String() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// buildSongFilter translates the filter into WHERE conditions, songs in the trash are left out
+func buildSongFilter(f models.SongFilter) *whereBuilder {
+	b := &whereBuilder{conditions: []string{"s.deleted_at IS NULL"}}
+
+	if f.Group != "" {
+		b.add("a.normalized_name = %s", models.NormalizeArtistName(f.Group))
+	}
+	if f.GroupContains != "" {
+		b.add("a.name ILIKE %s", containsPattern(f.GroupContains))
+	}
+	if f.SongContains != "" {
+		b.add("s.song ILIKE %s", containsPattern(f.SongContains))
+	}
+	if f.TextContains != "" {
+		b.add("s.text ILIKE %s", containsPattern(f.TextContains))
+	}
+	if !f.ReleasedFrom.IsZero() {
+		b.add("s.release_date >= %s::date", f.ReleasedFrom.Start().Format(time.DateOnly))
+	}
+	if !f.ReleasedTo.IsZero() {
+		b.add("s.release_date <= %s::date", f.ReleasedTo.End().Format(time.DateOnly))
+	}
+	if f.HasLink != nil {
+		if *f.HasLink {
+			b.conditions = append(b.conditions, "COALESCE(s.link, '') <> ''")
+		} else {
+			b.conditions = append(b.conditions, "COALESCE(s.link, '') = ''")
+		}
+	}
+
+	if len(f.Tags) > 0 {
+		b.add(`s.id IN (
+        SELECT st.song_id FROM song_tags st JOIN tags t ON t.id = st.tag_id
+        WHERE t.normalized_name = ANY(%s::text[])
+        GROUP BY st.song_id
+        HAVING COUNT(DISTINCT t.id) = %s)`, pq.Array(f.Tags), len(f.Tags))
+	}
+	if len(f.TagsAny) > 0 {
+		b.add(`EXISTS (
+        SELECT 1 FROM song_tags st JOIN tags t ON t.id = st.tag_id
+        WHERE st.song_id = s.id AND t.normalized_name = ANY(%s::text[]))`, pq.Array(f.TagsAny))
+	}
+	if len(f.TagsNone) > 0 {
+		b.add(`NOT EXISTS (
+        SELECT 1 FROM song_tags st JOIN tags t ON t.id = st.tag_id
+        WHERE st.song_id = s.id AND t.normalized_name = ANY(%s::text[]))`, pq.Array(f.TagsNone))
+	}
+
+	return b
+}
+
+// containsPattern builds an ILIKE pattern matching value as a literal substring
+func containsPattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(value) + "%"
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"musiclib/internal/models"
+	"time"
+)
+
+const (
+	// idempotencyKeyTTL is how long a completed request can be replayed
+	idempotencyKeyTTL = 24 * time.Hour
+	// idempotencyPendingTimeout frees keys of requests that never completed
+	idempotencyPendingTimeout = 5 * time.Minute
+)
+
+// ReserveIdempotencyKey claims the principal's key for the current request. When the key is already
+// taken reserved is false and the existing record is returned
+func (r *songRepository) ReserveIdempotencyKey(ctx context.Context, principal string, key string, requestHash string) (*models.IdempotencyRecord, bool, error) {
+	r.logger.Debug("Starting ReserveIdempotencyKey in repository", "principal", principal, "key", key)
+
+	now := time.Now()
+	var reservedKey string
+	err := r.db.GetContext(ctx, &reservedKey, reserveIdempotencyKey,
+		principal,
+		key,
+		requestHash,
+		now.Add(-idempotencyKeyTTL),
+		now.Add(-idempotencyPendingTimeout),
+	)
+	if err == nil {
+		return nil, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	var record models.IdempotencyRecord
+	if err := r.db.GetContext(ctx, &record, getIdempotencyKey, principal, key); err != nil {
+		return nil, false, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	return &record, false, nil
+}
+
+// CompleteIdempotencyKey stores the song created by the request holding the key
+func (r *songRepository) CompleteIdempotencyKey(ctx context.Context, principal string, key string, songID int) error {
+	if _, err := r.db.ExecContext(ctx, completeIdempotencyKey, principal, key, songID); err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey drops a pending key so the client can retry the request
+func (r *songRepository) ReleaseIdempotencyKey(ctx context.Context, principal string, key string) error {
+	if _, err := r.db.ExecContext(ctx, releaseIdempotencyKey, principal, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// PurgeIdempotencyKeys removes keys that can no longer be replayed
+func (r *songRepository) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, purgeIdempotencyKeys, time.Now().Add(-idempotencyKeyTTL))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
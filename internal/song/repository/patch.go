@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"musiclib/internal/models"
+	"strings"
+)
+
+// setBuilder collects the assignments of an UPDATE, placeholders are numbered like in whereBuilder
+type setBuilder struct {
+	whereBuilder
+}
+
+func (b *setBuilder) String() string {
+	return " SET " + strings.Join(b.conditions, ", ")
+}
+
+// setString assigns a text column, an explicit null in the patch stores NULL
+func (b *setBuilder) setString(column string, value models.Optional[string]) {
+	if value.Null {
+		b.conditions = append(b.conditions, column+" = NULL")
+		return
+	}
+	b.add(column+" = %s", value.Value)
+}
+
+// buildSongPatch turns the fields present in the patch into assignments, merged is the song
+// with the patch applied and its album position normalized
+func buildSongPatch(p models.SongPatch, merged models.Song) *setBuilder {
+	b := &setBuilder{}
+
+	if p.Group.Set {
+		b.add("artist_id = NULLIF(%s, 0)", merged.ArtistID)
+	}
+	if p.Song.Set {
+		b.setString("song", p.Song)
+	}
+	if p.ReleaseDate.Set {
+		b.add("release_date = %s", merged.ReleaseDate)
+		b.add("release_date_precision = NULLIF(%s, '')", string(merged.ReleaseDate.Precision))
+	}
+	if p.Text.Set {
+		b.setString("text", p.Text)
+	}
+	if p.Link.Set {
+		b.setString("link", p.Link)
+	}
+	// Album, disc and track are written together so the album check constraint always holds
+	if p.TouchesAlbum() {
+		b.add("album_id = NULLIF(%s, 0)", merged.AlbumID)
+		b.add("disc_number = NULLIF(%s, 0)", merged.DiscNumber)
+		b.add("track_number = NULLIF(%s, 0)", merged.TrackNumber)
+	}
+
+	return b
+}
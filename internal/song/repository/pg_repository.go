@@ -2,13 +2,28 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"musiclib/internal/models"
+	songDomain "musiclib/internal/song"
+	"musiclib/pkg/audit"
+	"musiclib/pkg/db/postgres"
 	"musiclib/pkg/logger"
+	"musiclib/pkg/lyrics"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// exportBatchSize is how many rows Export fetches from the cursor at a time
+const exportBatchSize = 500
+
+// purgeBatchSize is how many songs PurgeDeleted removes per transaction
+const purgeBatchSize = 500
+
 type songRepository struct {
 	db     *sqlx.DB
 	logger logger.Logger
@@ -21,50 +36,154 @@ func NewSongRepository(db *sqlx.DB, logger logger.Logger) *songRepository {
 	}
 }
 
-func (r *songRepository) GetList(sortBy string, sortOrder string, limit int, offset int) ([]models.Song, error) {
+func (r *songRepository) GetList(ctx context.Context, q models.SongListQuery) ([]models.Song, error) {
+	ctx, end := observe(ctx, "GetList")
+	defer end()
 	r.logger.Debug("Starting GetList in repository",
-		"sortBy", sortBy,
-		"sortOrder", sortOrder,
-		"limit", limit,
-		"offset", offset,
+		"filter", q.Filter,
+		"sortBy", q.SortBy,
+		"sortOrder", q.SortOrder,
+		"limit", q.Limit,
+		"offset", q.Offset,
 	)
 
-	// Формируем полный запрос
-	query := getList
+	// Формируем полный запрос с фильтрами
+	where := buildSongFilter(q.Filter)
+
+	// Колонка сортировки берется только из белого списка
+	sortColumn, ok := sortColumns[q.SortBy]
+	if !ok {
+		sortColumn = sortColumns["id"]
+	}
+	direction, comparison := " ASC", ">"
+	if q.SortOrder == "desc" {
+		direction, comparison = " DESC", "<"
+	}
 
-	// Добавляем сортировку
-	orderBy := " ORDER BY "
-	if sortBy == "" {
-		orderBy += "id"
-	} else {
-		orderBy += sortBy
+	// В режиме курсора продолжаем сразу после последней строки предыдущей страницы
+	if q.After != nil {
+		if sortColumn == sortColumns["id"] {
+			where.add("s.id "+comparison+" %s", q.After.ID)
+		} else {
+			where.add("("+sortColumn+", s.id) "+comparison+" (%s"+sortCasts[q.SortBy]+", %s)", q.After.Value, q.After.ID)
+		}
 	}
+	query := getList + where.String()
 
-	// Добавляем направление сортировки
-	if sortOrder == "desc" {
-		orderBy += " DESC"
-	} else {
-		orderBy += " ASC"
+	// Добавляем сортировку, при равных значениях порядок задает id
+	query += " ORDER BY " + sortColumn + direction
+	if sortColumn != sortColumns["id"] {
+		query += ", s.id" + direction
 	}
 
 	// Добавляем пагинацию
-	query += orderBy + " LIMIT $1 OFFSET $2"
+	query += " LIMIT " + where.arg(q.Limit)
+	if q.After == nil {
+		query += " OFFSET " + where.arg(q.Offset)
+	}
 
 	r.logger.Debug("Executing SQL query", "query", query)
 
 	// Выполняем запрос
-	rows, err := r.db.Query(query, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, where.args...)
 	if err != nil {
 		r.logger.Debug("Failed to execute query", "error", err)
 		return nil, fmt.Errorf("failed to get songs list: %v", err)
 	}
 	defer rows.Close()
 
+	songs, err := r.scanSongs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Debug("Successfully retrieved songs", "count", len(songs))
+	return songs, nil
+}
+
+// Export walks all songs matching the filter in id order through a server-side cursor,
+// so memory use doesn't depend on the size of the library. Iteration stops at the first error fn returns
+func (r *songRepository) Export(ctx context.Context, filter models.SongFilter, fn func(models.Song) error) error {
+	ctx, end := observe(ctx, "Export")
+	defer end()
+	r.logger.Debug("Starting Export in repository", "filter", filter)
+
+	where := buildSongFilter(filter)
+	query := getList + where.String() + " ORDER BY s.id"
+
+	// A read-only repeatable read transaction gives the whole export one consistent snapshot
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DECLARE songs_export NO SCROLL CURSOR FOR "+query, where.args...); err != nil {
+		r.logger.Debug("Failed to declare export cursor", "error", err)
+		return fmt.Errorf("failed to declare export cursor: %w", err)
+	}
+
+	exported := 0
+	for {
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf("FETCH %d FROM songs_export", exportBatchSize))
+		if err != nil {
+			return fmt.Errorf("failed to fetch songs: %w", err)
+		}
+		songs, err := r.scanSongs(rows)
+		rows.Close()
+		if err != nil {
+			return err
+		}
+
+		for _, song := range songs {
+			if err := fn(song); err != nil {
+				return err
+			}
+		}
+		exported += len(songs)
+
+		if len(songs) < exportBatchSize {
+			break
+		}
+	}
+
+	r.logger.Debug("Successfully exported songs", "count", exported)
+	return tx.Commit()
+}
+
+// GetTrash returns soft deleted songs, most recently deleted first
+func (r *songRepository) GetTrash(ctx context.Context, limit int, offset int) ([]models.Song, error) {
+	ctx, end := observe(ctx, "GetTrash")
+	defer end()
+	r.logger.Debug("Starting GetTrash in repository", "limit", limit, "offset", offset)
+
+	rows, err := r.db.QueryContext(ctx, getTrash, limit, offset)
+	if err != nil {
+		r.logger.Debug("Failed to execute query", "error", err)
+		return nil, fmt.Errorf("failed to get deleted songs: %w", err)
+	}
+	defer rows.Close()
+
+	songs, err := r.scanSongs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Debug("Successfully retrieved deleted songs", "count", len(songs))
+	return songs, nil
+}
+
+// scanSongs reads rows selected with the getList columns
+func (r *songRepository) scanSongs(rows *sql.Rows) ([]models.Song, error) {
 	songs := make([]models.Song, 0)
 
 	for rows.Next() {
 		var song models.Song
-		err := rows.Scan(&song.ID, &song.Group, &song.Song, &song.Text, &song.Link)
+		err := rows.Scan(
+			&song.ID, &song.ArtistID, &song.Group, &song.Song, &song.ReleaseDate, &song.Text, &song.Link,
+			&song.AlbumID, &song.DiscNumber, &song.TrackNumber, &song.DeletedAt,
+			&song.EnrichmentStatus, &song.EnrichmentError,
+		)
 		if err != nil {
 			r.logger.Debug("Failed to scan row", "error", err)
 			return nil, fmt.Errorf("failed to scan song: %v", err)
@@ -72,39 +191,157 @@ func (r *songRepository) GetList(sortBy string, sortOrder string, limit int, off
 		songs = append(songs, song)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		r.logger.Debug("Error iterating rows", "error", err)
 		return nil, fmt.Errorf("error iterating songs: %v", err)
 	}
 
-	r.logger.Debug("Successfully retrieved songs", "count", len(songs))
 	return songs, nil
 }
 
-func (r *songRepository) GetText(id int) (string, error) {
-	r.logger.Debug("Starting GetText in repository", "id", id)
+// Search runs a full-text search over titles, artists and lyrics, headlineConfig picks
+// the text search configuration used to highlight the snippets
+func (r *songRepository) Search(ctx context.Context, query string, headlineConfig string, limit int, offset int) ([]models.SongSearchResult, error) {
+	ctx, end := observe(ctx, "Search")
+	defer end()
+	r.logger.Debug("Starting Search in repository",
+		"query", query,
+		"headlineConfig", headlineConfig,
+		"limit", limit,
+		"offset", offset,
+	)
 
-	row := r.db.QueryRow(getText, id)
-	var text string
-	err := row.Scan(&text)
+	results := make([]models.SongSearchResult, 0)
+	err := r.db.SelectContext(ctx, &results, searchSongs, query, headlineConfig, limit, offset)
 	if err != nil {
-		r.logger.Debug("Failed to get song text", "error", err, "id", id)
-		return "", fmt.Errorf("failed to get song text: %v", err)
+		r.logger.Debug("Failed to search songs", "error", err)
+		return nil, fmt.Errorf("failed to search songs: %w", err)
 	}
 
-	r.logger.Debug("Successfully retrieved song text", 
-		"id", id,
-		"textLength", len(text),
-	)
-	return text, nil
+	r.logger.Debug("Successfully searched songs", "count", len(results))
+	return results, nil
 }
 
-func (r *songRepository) Delete(id int) error {
+// Delete moves the song to the trash, it can be restored until it is purged
+func (r *songRepository) Delete(ctx context.Context, id int) error {
+	ctx, end := observe(ctx, "Delete")
+	defer end()
 	r.logger.Debug("Starting Delete in repository", "id", id)
 
-	result, err := r.db.Exec(deleteSong, id)
+	return r.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.execByID(ctx, tx, deleteSong, id); err != nil {
+			return err
+		}
+		return r.writeRevision(ctx, tx, id, models.RevisionDelete)
+	})
+}
+
+// HardDelete removes the song permanently, whether it is in the trash or not
+func (r *songRepository) HardDelete(ctx context.Context, id int) error {
+	ctx, end := observe(ctx, "HardDelete")
+	defer end()
+	r.logger.Debug("Starting HardDelete in repository", "id", id)
+
+	return r.inTx(ctx, func(tx *sqlx.Tx) error {
+		// The last snapshot is taken before the row disappears
+		song, err := r.getSong(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := r.execByID(ctx, tx, hardDeleteSong, id); err != nil {
+			return err
+		}
+		return r.insertRevision(ctx, tx, song, models.RevisionHardDelete)
+	})
+}
+
+// Restore brings a song back from the trash
+func (r *songRepository) Restore(ctx context.Context, id int) error {
+	ctx, end := observe(ctx, "Restore")
+	defer end()
+	r.logger.Debug("Starting Restore in repository", "id", id)
+
+	return r.inTx(ctx, func(tx *sqlx.Tx) error {
+		err := r.execByID(ctx, tx, restoreSong, id)
+		if postgres.IsUniqueViolation(err) {
+			return uniqueViolationError(err)
+		}
+		if err != nil {
+			return err
+		}
+		return r.writeRevision(ctx, tx, id, models.RevisionRestore)
+	})
+}
+
+// PurgeDeleted permanently removes songs that were moved to the trash before the given time
+func (r *songRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	ctx, end := observe(ctx, "PurgeDeleted")
+	defer end()
+	r.logger.Debug("Starting PurgeDeleted in repository", "before", before)
+
+	var purged int64
+	for {
+		batch, err := r.purgeBatch(ctx, before)
+		purged += batch
+		if err != nil {
+			r.logger.Debug("Failed to purge songs", "error", err)
+			return purged, err
+		}
+		if batch < purgeBatchSize {
+			break
+		}
+	}
+
+	r.logger.Debug("Successfully purged songs", "count", purged)
+	return purged, nil
+}
+
+// purgeBatch removes one batch of expired songs, each keeps a purge revision with its last snapshot
+func (r *songRepository) purgeBatch(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := r.inTx(ctx, func(tx *sqlx.Tx) error {
+		rows, err := tx.QueryContext(ctx, getPurgeableSongs, before, purgeBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get songs to purge: %w", err)
+		}
+		songs, err := r.scanSongs(rows)
+		rows.Close()
+		if err != nil {
+			return err
+		}
+		if len(songs) == 0 {
+			return nil
+		}
+
+		ids := make([]int64, len(songs))
+		for i := range songs {
+			if err := r.insertRevision(ctx, tx, &songs[i], models.RevisionPurge); err != nil {
+				return err
+			}
+			ids[i] = int64(songs[i].ID)
+		}
+
+		result, err := tx.ExecContext(ctx, purgeSongs, pq.Array(ids))
+		if err != nil {
+			return fmt.Errorf("failed to purge songs: %w", err)
+		}
+		purged, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// execByID runs a statement affecting a single song, ErrNotFound is returned when nothing matched
+func (r *songRepository) execByID(ctx context.Context, tx *sqlx.Tx, query string, id int) error {
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
-		r.logger.Debug("Failed to delete song", "error", err, "id", id)
+		r.logger.Debug("Failed to execute query", "error", err, "id", id)
 		return err
 	}
 
@@ -113,60 +350,183 @@ func (r *songRepository) Delete(id int) error {
 		r.logger.Debug("Failed to get rows affected", "error", err)
 		return err
 	}
+	if rowsAffected == 0 {
+		return songDomain.ErrNotFound
+	}
 
-	r.logger.Debug("Successfully deleted song", 
+	r.logger.Debug("Successfully executed query", 
 		"id", id,
 		"rowsAffected", rowsAffected,
 	)
 	return nil
 }
 
-func (r *songRepository) Update(song *models.Song) error {
+func (r *songRepository) Update(ctx context.Context, song *models.Song) error {
+	ctx, end := observe(ctx, "Update")
+	defer end()
 	r.logger.Debug("Starting Update in repository", 
 		"id", song.ID,
 		"group", song.Group,
 		"song", song.Song,
 	)
 
+	err := r.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.update(ctx, tx, song); err != nil {
+			return err
+		}
+		return r.writeRevision(ctx, tx, song.ID, models.RevisionUpdate)
+	})
+	if err != nil {
+		return err
+	}
+
+	r.logger.Debug("Successfully updated song", "id", song.ID)
+	return nil
+}
+
+// update writes all song fields and splits the text into stanzas again
+func (r *songRepository) update(ctx context.Context, tx *sqlx.Tx, song *models.Song) error {
 	var id int
-	err := r.db.QueryRow(updateSong, 
-		song.Group, 
+	err := tx.QueryRowContext(ctx, updateSong, 
+		song.ArtistID, 
 		song.Song, 
 		song.Text, 
 		song.Link, 
 		song.ReleaseDate, 
+		string(song.ReleaseDate.Precision),
+		song.AlbumID,
+		song.DiscNumber,
+		song.TrackNumber,
 		song.ID,
 	).Scan(&id)
 
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return songDomain.ErrNotFound
+		}
+		if postgres.IsForeignKeyViolation(err) {
+			return foreignKeyViolationError(err)
+		}
+		if postgres.IsUniqueViolation(err) {
+			return uniqueViolationError(err)
+		}
 		r.logger.Debug("Failed to update song", 
 			"error", err,
 			"id", song.ID,
 		)
-		return fmt.Errorf("failed to update song: %v", err)
+		return fmt.Errorf("failed to update song: %w", err)
 	}
 
-	r.logger.Debug("Successfully updated song", "id", id)
-	return nil
+	// Разбиваем текст на строфы заново
+	return r.replaceStanzas(ctx, tx, id, song.Text)
+}
+
+// Patch applies a merge patch, only the columns present in the patch are written
+func (r *songRepository) Patch(ctx context.Context, id int, patch models.SongPatch) (*models.Song, error) {
+	ctx, end := observe(ctx, "Patch")
+	defer end()
+	r.logger.Debug("Starting Patch in repository", "id", id)
+
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result *models.Song
+	err := r.inTx(ctx, func(tx *sqlx.Tx) error {
+		current, err := r.querySong(ctx, tx, getSongForUpdate, id)
+		if err != nil {
+			return err
+		}
+
+		merged := *current
+		patch.ApplyTo(&merged)
+		if err := merged.NormalizeAlbumPosition(); err != nil {
+			return err
+		}
+
+		set := buildSongPatch(patch, merged)
+		if len(set.conditions) == 0 {
+			result = current
+			return nil
+		}
+		query := "UPDATE songs" + set.String() + " WHERE id = " + set.arg(id)
+
+		r.logger.Debug("Executing SQL query", "query", query)
+
+		if _, err := tx.ExecContext(ctx, query, set.args...); err != nil {
+			if postgres.IsForeignKeyViolation(err) {
+				return foreignKeyViolationError(err)
+			}
+			if postgres.IsUniqueViolation(err) {
+				return uniqueViolationError(err)
+			}
+			r.logger.Debug("Failed to patch song", "error", err, "id", id)
+			return fmt.Errorf("failed to patch song: %w", err)
+		}
+
+		if patch.Text.Set {
+			if err := r.replaceStanzas(ctx, tx, id, merged.Text); err != nil {
+				return err
+			}
+		}
+
+		result, err = r.getSong(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		return r.insertRevision(ctx, tx, result, models.RevisionUpdate)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Debug("Successfully patched song", "id", id)
+	return result, nil
 }
 
 func (r *songRepository) Create(ctx context.Context, song *models.Song) (*models.Song, error) {
+	ctx, end := observe(ctx, "Create")
+	defer end()
 	r.logger.Debug("Starting Create in repository", 
 		"group", song.Group,
 		"song", song.Song,
 	)
 
 	var id int
-	err := r.db.QueryRowContext(ctx, createSong,
-		song.Group,
-		song.Song,
-		song.ReleaseDate,
-		song.Text,
-		song.Link,
-	).Scan(&id)
+	err := r.inTx(ctx, func(tx *sqlx.Tx) error {
+		err := tx.QueryRowContext(ctx, createSong,
+			song.ArtistID,
+			song.Song,
+			song.ReleaseDate,
+			string(song.ReleaseDate.Precision),
+			song.Text,
+			song.Link,
+			song.EnrichmentStatus,
+			song.EnrichmentError,
+		).Scan(&id)
+
+		if err != nil {
+			if postgres.IsUniqueViolation(err) {
+				return uniqueViolationError(err)
+			}
+			if postgres.IsForeignKeyViolation(err) {
+				return foreignKeyViolationError(err)
+			}
+			r.logger.Debug("Failed to create song", "error", err)
+			return err
+		}
 
+		if err := r.replaceStanzas(ctx, tx, id, song.Text); err != nil {
+			return err
+		}
+		if song.EnrichmentStatus == models.EnrichmentPending {
+			if _, err := tx.ExecContext(ctx, enqueueEnrichment, id); err != nil {
+				return fmt.Errorf("failed to enqueue song enrichment: %w", err)
+			}
+		}
+		return r.writeRevision(ctx, tx, id, models.RevisionCreate)
+	})
 	if err != nil {
-		r.logger.Debug("Failed to create song", "error", err)
 		return nil, err
 	}
 
@@ -174,3 +534,420 @@ func (r *songRepository) Create(ctx context.Context, song *models.Song) (*models
 	r.logger.Debug("Successfully created song", "id", id)
 	return song, nil
 }
+
+// GetRevisions returns the history of a song, newest revision first
+func (r *songRepository) GetRevisions(ctx context.Context, songID int, limit int, offset int) ([]models.SongRevision, error) {
+	ctx, end := observe(ctx, "GetRevisions")
+	defer end()
+	r.logger.Debug("Starting GetRevisions in repository", "songID", songID, "limit", limit, "offset", offset)
+
+	rows := make([]revisionRow, 0)
+	if err := r.db.SelectContext(ctx, &rows, getRevisions, songID, limit, offset); err != nil {
+		r.logger.Debug("Failed to get song revisions", "error", err, "songID", songID)
+		return nil, fmt.Errorf("failed to get song revisions: %w", err)
+	}
+
+	revisions := make([]models.SongRevision, 0, len(rows))
+	for _, row := range rows {
+		revision, err := row.toModel()
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	r.logger.Debug("Successfully retrieved song revisions", "songID", songID, "count", len(revisions))
+	return revisions, nil
+}
+
+// GetRevision returns a single revision of a song
+func (r *songRepository) GetRevision(ctx context.Context, songID int, revision int) (*models.SongRevision, error) {
+	ctx, end := observe(ctx, "GetRevision")
+	defer end()
+	r.logger.Debug("Starting GetRevision in repository", "songID", songID, "revision", revision)
+
+	var row revisionRow
+	if err := r.db.GetContext(ctx, &row, getRevision, songID, revision); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, songDomain.ErrRevisionNotFound
+		}
+		return nil, fmt.Errorf("failed to get song revision: %w", err)
+	}
+
+	result, err := row.toModel()
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Rollback restores the song fields from a previous revision, the rollback itself becomes a new revision
+func (r *songRepository) Rollback(ctx context.Context, songID int, revision int) (*models.Song, error) {
+	ctx, end := observe(ctx, "Rollback")
+	defer end()
+	r.logger.Debug("Starting Rollback in repository", "songID", songID, "revision", revision)
+
+	target, err := r.GetRevision(ctx, songID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	song := target.Snapshot
+	song.ID = songID
+
+	var result *models.Song
+	err = r.inTx(ctx, func(tx *sqlx.Tx) error {
+		err := r.update(ctx, tx, &song)
+		if errors.Is(err, songDomain.ErrAlbumNotFound) || errors.Is(err, songDomain.ErrArtistNotFound) {
+			return songDomain.ErrRevisionConflict
+		}
+		if err != nil {
+			return err
+		}
+
+		result, err = r.getSong(ctx, tx, songID)
+		if err != nil {
+			return err
+		}
+		return r.insertRevision(ctx, tx, result, models.RevisionRollback)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Debug("Successfully rolled back song", "songID", songID, "revision", revision)
+	return result, nil
+}
+
+// writeRevision records the current state of the song as its next revision
+func (r *songRepository) writeRevision(ctx context.Context, tx *sqlx.Tx, id int, action string) error {
+	song, err := r.getSong(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	return r.insertRevision(ctx, tx, song, action)
+}
+
+func (r *songRepository) insertRevision(ctx context.Context, tx *sqlx.Tx, song *models.Song, action string) error {
+	snapshot, err := json.Marshal(song)
+	if err != nil {
+		return fmt.Errorf("failed to marshal song snapshot: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, insertRevision, song.ID, action, snapshot, audit.Actor(ctx), audit.Source(ctx))
+	if err != nil {
+		r.logger.Debug("Failed to insert song revision", "error", err, "id", song.ID)
+		return fmt.Errorf("failed to insert song revision: %w", err)
+	}
+	return nil
+}
+
+// getSong reads a song inside the transaction, including songs in the trash
+func (r *songRepository) getSong(ctx context.Context, tx *sqlx.Tx, id int) (*models.Song, error) {
+	return r.querySong(ctx, tx, getSongByID, id)
+}
+
+// querySong runs a getList based query selecting a single song by id
+func (r *songRepository) querySong(ctx context.Context, tx *sqlx.Tx, query string, id int) (*models.Song, error) {
+	rows, err := tx.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get song: %w", err)
+	}
+	defer rows.Close()
+
+	songs, err := r.scanSongs(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(songs) == 0 {
+		return nil, songDomain.ErrNotFound
+	}
+	return &songs[0], nil
+}
+
+// inTx runs fn in a transaction that is committed only when fn succeeds
+func (r *songRepository) inTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a song that is not in the trash
+func (r *songRepository) GetByID(ctx context.Context, id int) (*models.Song, error) {
+	ctx, end := observe(ctx, "GetByID")
+	defer end()
+	r.logger.Debug("Starting GetByID in repository", "id", id)
+	return r.findSong(ctx, getActiveSong, id)
+}
+
+// FindByTitle looks a song up by artist name and title, both compared the normalized way
+func (r *songRepository) FindByTitle(ctx context.Context, group string, title string) (*models.Song, error) {
+	ctx, end := observe(ctx, "FindByTitle")
+	defer end()
+	r.logger.Debug("Starting FindByTitle in repository", "group", group, "song", title)
+	return r.findSong(ctx, findSongByTitle, models.NormalizeArtistName(group), title)
+}
+
+func (r *songRepository) findSong(ctx context.Context, query string, args ...interface{}) (*models.Song, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Debug("Failed to execute query", "error", err)
+		return nil, fmt.Errorf("failed to get song: %w", err)
+	}
+	defer rows.Close()
+
+	songs, err := r.scanSongs(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(songs) == 0 {
+		return nil, songDomain.ErrNotFound
+	}
+	return &songs[0], nil
+}
+
+// uniqueViolationError tells a duplicate title apart from a taken album position
+func uniqueViolationError(err error) error {
+	if postgres.IsUniqueViolationOf(err, "idx_songs_artist_title") {
+		return songDomain.ErrDuplicateSong
+	}
+	return songDomain.ErrTrackPositionTaken
+}
+
+// foreignKeyViolationError tells a missing artist apart from a missing album
+func foreignKeyViolationError(err error) error {
+	switch {
+	case postgres.IsForeignKeyViolationOf(err, "songs_artist_id_fkey"):
+		return songDomain.ErrArtistNotFound
+	case postgres.IsForeignKeyViolationOf(err, "songs_album_id_fkey"):
+		return songDomain.ErrAlbumNotFound
+	default:
+		return fmt.Errorf("failed to write song: %w", err)
+	}
+}
+
+// GetStanzas returns the song lyrics split into ordered stanzas
+func (r *songRepository) GetStanzas(ctx context.Context, id int) ([]models.Stanza, error) {
+	ctx, end := observe(ctx, "GetStanzas")
+	defer end()
+	r.logger.Debug("Starting GetStanzas in repository", "id", id)
+
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, songExists, id); err != nil {
+		return nil, fmt.Errorf("failed to check song: %w", err)
+	}
+	if !exists {
+		return nil, songDomain.ErrNotFound
+	}
+
+	stanzas := make([]models.Stanza, 0)
+	if err := r.db.SelectContext(ctx, &stanzas, getStanzas, id); err != nil {
+		r.logger.Debug("Failed to get song stanzas", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to get song stanzas: %w", err)
+	}
+
+	r.logger.Debug("Successfully retrieved song stanzas", "id", id, "count", len(stanzas))
+	return stanzas, nil
+}
+
+// ResplitStanzas rebuilds the stanzas of up to limit songs queued by the stanza backfill migration,
+// it returns how many songs were done so the caller can stop at 0
+func (r *songRepository) ResplitStanzas(ctx context.Context, limit int) (int, error) {
+	ctx, end := observe(ctx, "ResplitStanzas")
+	defer end()
+
+	var done int
+	err := r.inTx(ctx, func(tx *sqlx.Tx) error {
+		var songs []models.Song
+		if err := tx.SelectContext(ctx, &songs, claimStanzaBackfill, limit); err != nil {
+			return fmt.Errorf("failed to claim stanza backfill: %w", err)
+		}
+
+		ids := make([]int64, len(songs))
+		for i, s := range songs {
+			if err := r.replaceStanzas(ctx, tx, s.ID, s.Text); err != nil {
+				return err
+			}
+			ids[i] = int64(s.ID)
+		}
+
+		if _, err := tx.ExecContext(ctx, deleteStanzaBackfill, pq.Array(ids)); err != nil {
+			return fmt.Errorf("failed to delete stanza backfill: %w", err)
+		}
+		done = len(songs)
+		return nil
+	})
+	if err != nil {
+		r.logger.Debug("Failed to resplit stanzas", "error", err)
+		return 0, err
+	}
+	return done, nil
+}
+
+// replaceStanzas stores text split into stanzas in place of the previous ones
+func (r *songRepository) replaceStanzas(ctx context.Context, tx *sqlx.Tx, songID int, text string) error {
+	if _, err := tx.ExecContext(ctx, deleteStanzas, songID); err != nil {
+		return fmt.Errorf("failed to delete song stanzas: %w", err)
+	}
+
+	stanzas := lyrics.Split(text)
+	if len(stanzas) == 0 {
+		return nil
+	}
+
+	types := make([]string, len(stanzas))
+	texts := make([]string, len(stanzas))
+	for i, stanza := range stanzas {
+		types[i] = stanza.Type
+		texts[i] = stanza.Text()
+	}
+
+	if _, err := tx.ExecContext(ctx, insertStanzas, songID, pq.Array(types), pq.Array(texts)); err != nil {
+		return fmt.Errorf("failed to insert song stanzas: %w", err)
+	}
+	return nil
+}
+
+// ClaimEnrichmentJob leases the enrichment job due first, ErrNoEnrichmentJobs is returned when none is due
+func (r *songRepository) ClaimEnrichmentJob(ctx context.Context, lease time.Duration) (*models.EnrichmentJob, error) {
+	ctx, end := observe(ctx, "ClaimEnrichmentJob")
+	defer end()
+
+	var job models.EnrichmentJob
+	if err := r.db.GetContext(ctx, &job, claimEnrichmentJob, lease.Seconds()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, songDomain.ErrNoEnrichmentJobs
+		}
+		r.logger.Debug("Failed to claim enrichment job", "error", err)
+		return nil, fmt.Errorf("failed to claim enrichment job: %w", err)
+	}
+	return &job, nil
+}
+
+// CompleteEnrichment fills the release date, text and link the song is still missing from detail
+// and marks it enriched, values set in the meantime are kept
+func (r *songRepository) CompleteEnrichment(ctx context.Context, songID int, detail *models.Song) (*models.Song, error) {
+	ctx, end := observe(ctx, "CompleteEnrichment")
+	defer end()
+	r.logger.Debug("Starting CompleteEnrichment in repository", "id", songID)
+
+	var result *models.Song
+	err := r.inTx(ctx, func(tx *sqlx.Tx) error {
+		current, err := r.querySong(ctx, tx, getSongForUpdate, songID)
+		if err != nil {
+			return err
+		}
+
+		merged := *current
+		if merged.Text == "" {
+			merged.Text = detail.Text
+		}
+		if merged.Link == "" {
+			merged.Link = detail.Link
+		}
+		if merged.ReleaseDate.IsZero() {
+			merged.ReleaseDate = detail.ReleaseDate
+		}
+		if merged != *current {
+			if err := r.update(ctx, tx, &merged); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, setEnrichmentStatus, songID, models.EnrichmentEnriched, ""); err != nil {
+			return fmt.Errorf("failed to set enrichment status: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, deleteEnrichmentJob, songID); err != nil {
+			return fmt.Errorf("failed to delete enrichment job: %w", err)
+		}
+
+		result, err = r.getSong(ctx, tx, songID)
+		if err != nil {
+			return err
+		}
+		return r.insertRevision(ctx, tx, result, models.RevisionUpdate)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Debug("Successfully enriched song", "id", songID)
+	return result, nil
+}
+
+// RescheduleEnrichment keeps the job for another attempt at at
+func (r *songRepository) RescheduleEnrichment(ctx context.Context, songID int, reason string, at time.Time) error {
+	ctx, end := observe(ctx, "RescheduleEnrichment")
+	defer end()
+
+	if _, err := r.db.ExecContext(ctx, rescheduleEnrichment, songID, reason, at); err != nil {
+		r.logger.Debug("Failed to reschedule enrichment", "error", err, "id", songID)
+		return fmt.Errorf("failed to reschedule enrichment: %w", err)
+	}
+	return nil
+}
+
+// FailEnrichment gives up on the song's job and marks the song failed with reason
+func (r *songRepository) FailEnrichment(ctx context.Context, songID int, reason string) error {
+	ctx, end := observe(ctx, "FailEnrichment")
+	defer end()
+
+	return r.inTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, setEnrichmentStatus, songID, models.EnrichmentFailed, reason); err != nil {
+			return fmt.Errorf("failed to set enrichment status: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, deleteEnrichmentJob, songID); err != nil {
+			return fmt.Errorf("failed to delete enrichment job: %w", err)
+		}
+		return nil
+	})
+}
+
+// RetryEnrichment queues a song whose enrichment failed again
+func (r *songRepository) RetryEnrichment(ctx context.Context, songID int) (*models.Song, error) {
+	ctx, end := observe(ctx, "RetryEnrichment")
+	defer end()
+	r.logger.Debug("Starting RetryEnrichment in repository", "id", songID)
+
+	var result *models.Song
+	err := r.inTx(ctx, func(tx *sqlx.Tx) error {
+		err := r.execByID(ctx, tx, retryEnrichment, songID)
+		if errors.Is(err, songDomain.ErrNotFound) {
+			var exists bool
+			if err := tx.GetContext(ctx, &exists, songExists, songID); err != nil {
+				return fmt.Errorf("failed to check song: %w", err)
+			}
+			if exists {
+				return songDomain.ErrEnrichmentNotFailed
+			}
+			return songDomain.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to retry enrichment: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, enqueueEnrichment, songID); err != nil {
+			return fmt.Errorf("failed to enqueue song enrichment: %w", err)
+		}
+
+		result, err = r.getSong(ctx, tx, songID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Debug("Successfully queued song enrichment", "id", songID)
+	return result, nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"errors"
+	songDomain "musiclib/internal/song"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestConstraintViolationErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		translate  func(error) error
+		want       error
+		wantOpaque bool
+	}{
+		{"missing artist", &pq.Error{Code: "23503", Constraint: "songs_artist_id_fkey"}, foreignKeyViolationError, songDomain.ErrArtistNotFound, false},
+		{"missing album", &pq.Error{Code: "23503", Constraint: "songs_album_id_fkey"}, foreignKeyViolationError, songDomain.ErrAlbumNotFound, false},
+		{"other foreign key", &pq.Error{Code: "23503", Constraint: "song_tags_tag_id_fkey"}, foreignKeyViolationError, nil, true},
+		{"duplicate title", &pq.Error{Code: "23505", Constraint: "idx_songs_artist_title"}, uniqueViolationError, songDomain.ErrDuplicateSong, false},
+		{"taken track", &pq.Error{Code: "23505", Constraint: "idx_songs_album_track"}, uniqueViolationError, songDomain.ErrTrackPositionTaken, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.translate(tt.err)
+			if tt.wantOpaque {
+				if errors.Is(got, songDomain.ErrArtistNotFound) || errors.Is(got, songDomain.ErrAlbumNotFound) {
+					t.Errorf("got %v, want the database error kept", got)
+				}
+				return
+			}
+			if !errors.Is(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"musiclib/internal/models"
+)
+
+// revisionRow is a song_revisions row with the snapshot still encoded
+type revisionRow struct {
+	models.SongRevision
+	SnapshotJSON []byte `db:"snapshot"`
+}
+
+func (r revisionRow) toModel() (models.SongRevision, error) {
+	revision := r.SongRevision
+	if err := json.Unmarshal(r.SnapshotJSON, &revision.Snapshot); err != nil {
+		return models.SongRevision{}, fmt.Errorf("failed to decode revision %d of song %d: %w", r.Revision, r.SongID, err)
+	}
+	return revision, nil
+}
@@ -1,19 +1,184 @@
 package repository
 
-const getList = `SELECT id, group_name, song, text, link FROM songs`
+const getList = `
+    SELECT s.id, COALESCE(s.artist_id, 0), COALESCE(a.name, '') AS group_name, COALESCE(s.song, ''),
+           format_release_date(s.release_date, s.release_date_precision), COALESCE(s.text, ''), COALESCE(s.link, ''),
+           COALESCE(s.album_id, 0), COALESCE(s.disc_number, 0), COALESCE(s.track_number, 0), s.deleted_at,
+           s.enrichment_status, s.enrichment_error
+    FROM songs s
+    LEFT JOIN artists a ON a.id = s.artist_id`
 
-const getText = `SELECT text FROM songs WHERE id = $1`
+const getTrash = getList + `
+    WHERE s.deleted_at IS NOT NULL
+    ORDER BY s.deleted_at DESC, s.id
+    LIMIT $1 OFFSET $2`
 
-const deleteSong = `DELETE FROM songs WHERE id = $1`
+const getSongByID = getList + ` WHERE s.id = $1`
+
+const getActiveSong = getList + ` WHERE s.id = $1 AND s.deleted_at IS NULL`
+
+const findSongByTitle = getList + `
+    WHERE a.normalized_name = $1 AND s.normalized_title = normalize_song_title($2) AND s.deleted_at IS NULL`
+
+const getSongForUpdate = getList + ` WHERE s.id = $1 AND s.deleted_at IS NULL FOR UPDATE OF s`
+
+const songExists = `SELECT EXISTS (SELECT 1 FROM songs WHERE id = $1 AND deleted_at IS NULL)`
+
+const getStanzas = `SELECT position, COALESCE(type, '') AS type, text FROM song_stanzas WHERE song_id = $1 ORDER BY position`
+
+const deleteStanzas = `DELETE FROM song_stanzas WHERE song_id = $1`
+
+// claimStanzaBackfill locks a batch of songs waiting for their stanzas to be rebuilt
+const claimStanzaBackfill = `
+    SELECT b.song_id AS id, COALESCE(s.text, '') AS text
+    FROM stanza_backfill b
+    JOIN songs s ON s.id = b.song_id
+    ORDER BY b.song_id
+    LIMIT $1
+    FOR UPDATE OF b SKIP LOCKED`
+
+const deleteStanzaBackfill = `DELETE FROM stanza_backfill WHERE song_id = ANY($1)`
+
+const insertStanzas = `
+    INSERT INTO song_stanzas (song_id, position, type, text)
+    SELECT $1, u.n - 1, NULLIF(u.type, ''), u.text
+    FROM unnest($2::text[], $3::text[]) WITH ORDINALITY AS u(type, text, n)`
+
+const deleteSong = `UPDATE songs SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+
+const hardDeleteSong = `DELETE FROM songs WHERE id = $1`
+
+const restoreSong = `UPDATE songs SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+
+// getPurgeableSongs locks a batch of songs that stayed in the trash past the retention period
+const getPurgeableSongs = getList + `
+    WHERE s.deleted_at < $1
+    ORDER BY s.id
+    LIMIT $2
+    FOR UPDATE OF s SKIP LOCKED`
+
+const purgeSongs = `DELETE FROM songs WHERE id = ANY($1)`
 
 const updateSong = `
     UPDATE songs 
-    SET group_name = $1, 
+    SET artist_id = NULLIF($1, 0), 
         song = $2, 
         text = $3, 
         link = $4,
-        release_date = $5 
-    WHERE id = $6
+        release_date = $5,
+        release_date_precision = NULLIF($6, ''),
+        album_id = NULLIF($7, 0),
+        disc_number = NULLIF($8, 0),
+        track_number = NULLIF($9, 0)
+    WHERE id = $10 AND deleted_at IS NULL
+    RETURNING id`
+
+const createSong = `
+    INSERT INTO songs (artist_id, song, release_date, release_date_precision, text, link, enrichment_status, enrichment_error)
+    VALUES (NULLIF($1, 0), $2, $3, NULLIF($4, ''), $5, $6, COALESCE(NULLIF($7, ''), 'enriched'), $8)
     RETURNING id`
 
-const createSong = `INSERT INTO songs (group_name, song, release_date, text, link) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+// searchSongs matches the query against both text configurations, verse_index and stanza_index
+// point to the first matching line, numbered the same way GET /songs/text numbers lines and stanzas
+const searchSongs = `
+    WITH q AS (
+        SELECT websearch_to_tsquery('english', $1) || websearch_to_tsquery('russian', $1) AS query
+    )
+    SELECT s.id,
+           COALESCE(s.artist_id, 0) AS artist_id,
+           COALESCE(a.name, '') AS group_name,
+           COALESCE(s.song, '') AS song,
+           ts_rank_cd(s.search_vector, q.query) AS rank,
+           ts_headline($2::regconfig, COALESCE(s.text, ''), q.query,
+                       'MaxFragments=2, MinWords=5, MaxWords=20') AS snippet,
+           verse.idx AS verse_index,
+           verse.stanza AS stanza_index
+    FROM songs s
+    CROSS JOIN q
+    LEFT JOIN artists a ON a.id = s.artist_id
+    LEFT JOIN LATERAL (
+        SELECT lines.idx - 1 AS idx, lines.stanza
+        FROM (
+            SELECT row_number() OVER (ORDER BY st.position, l.n) AS idx, st.position AS stanza, l.line
+            FROM song_stanzas st
+            CROSS JOIN LATERAL regexp_split_to_table(st.text, E'\n') WITH ORDINALITY AS l(line, n)
+            WHERE st.song_id = s.id
+        ) lines
+        WHERE (to_tsvector('english', lines.line) || to_tsvector('russian', lines.line)) @@ q.query
+        ORDER BY lines.idx
+        LIMIT 1
+    ) verse ON true
+    WHERE s.search_vector @@ q.query AND s.deleted_at IS NULL
+    ORDER BY rank DESC, s.id
+    LIMIT $3 OFFSET $4`
+
+const insertRevision = `
+    INSERT INTO song_revisions (song_id, revision, action, snapshot, actor, source)
+    SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5
+    FROM song_revisions
+    WHERE song_id = $1`
+
+const revisionColumns = `song_id, revision, action, snapshot, actor, source, created_at`
+
+const getRevisions = `
+    SELECT ` + revisionColumns + `
+    FROM song_revisions
+    WHERE song_id = $1
+    ORDER BY revision DESC
+    LIMIT $2 OFFSET $3`
+
+const getRevision = `SELECT ` + revisionColumns + ` FROM song_revisions WHERE song_id = $1 AND revision = $2`
+
+// reserveIdempotencyKey takes over keys that expired or were abandoned while pending,
+// no row is returned when the key is held by another request. Keys are scoped by the caller in $1
+const reserveIdempotencyKey = `
+    INSERT INTO idempotency_keys (principal, key, request_hash)
+    VALUES ($1, $2, $3)
+    ON CONFLICT (principal, key) DO UPDATE
+        SET request_hash = EXCLUDED.request_hash, song_id = NULL, created_at = NOW()
+        WHERE idempotency_keys.created_at < $4
+           OR (idempotency_keys.song_id IS NULL AND idempotency_keys.created_at < $5)
+    RETURNING key`
+
+const getIdempotencyKey = `
+    SELECT principal, key, request_hash, COALESCE(song_id, 0) AS song_id, created_at
+    FROM idempotency_keys
+    WHERE principal = $1 AND key = $2`
+
+const completeIdempotencyKey = `UPDATE idempotency_keys SET song_id = $3 WHERE principal = $1 AND key = $2`
+
+const releaseIdempotencyKey = `DELETE FROM idempotency_keys WHERE principal = $1 AND key = $2 AND song_id IS NULL`
+
+const purgeIdempotencyKeys = `DELETE FROM idempotency_keys WHERE created_at < $1`
+
+const enqueueEnrichment = `
+    INSERT INTO enrichment_jobs (song_id)
+    VALUES ($1)
+    ON CONFLICT (song_id) DO UPDATE SET attempts = 0, last_error = '', next_attempt_at = NOW()`
+
+// claimEnrichmentJob leases the job due first for $1 seconds, a worker that dies leaves it to be
+// claimed again once the lease runs out. SKIP LOCKED lets several workers claim jobs concurrently
+const claimEnrichmentJob = `
+    UPDATE enrichment_jobs j
+    SET attempts = j.attempts + 1, next_attempt_at = NOW() + make_interval(secs => $1)
+    FROM songs s
+    LEFT JOIN artists a ON a.id = s.artist_id
+    WHERE j.song_id = (
+        SELECT ej.song_id FROM enrichment_jobs ej
+        JOIN songs es ON es.id = ej.song_id
+        WHERE ej.next_attempt_at <= NOW() AND es.deleted_at IS NULL
+        ORDER BY ej.next_attempt_at
+        LIMIT 1
+        FOR UPDATE OF ej SKIP LOCKED
+    ) AND s.id = j.song_id
+    RETURNING j.song_id, COALESCE(a.name, '') AS group_name, COALESCE(s.song, '') AS song, j.attempts`
+
+const rescheduleEnrichment = `UPDATE enrichment_jobs SET last_error = $2, next_attempt_at = $3 WHERE song_id = $1`
+
+const deleteEnrichmentJob = `DELETE FROM enrichment_jobs WHERE song_id = $1`
+
+const setEnrichmentStatus = `UPDATE songs SET enrichment_status = $2, enrichment_error = $3 WHERE id = $1`
+
+const retryEnrichment = `
+    UPDATE songs SET enrichment_status = 'pending', enrichment_error = ''
+    WHERE id = $1 AND deleted_at IS NULL AND enrichment_status = 'failed'`
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"musiclib/pkg/metrics"
+
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("musiclib/internal/song/repository")
+
+// observe opens the span of a repository method and times it, the statements the method runs
+// become child spans carrying their SQL. The returned function ends both
+func observe(ctx context.Context, method string) (context.Context, func()) {
+	ctx, span := tracer.Start(ctx, "song.Repository/"+method, trace.WithAttributes(
+		semconv.CodeNamespace("song.Repository"),
+		semconv.CodeFunction(method),
+		semconv.DBSystemPostgreSQL,
+	))
+	done := metrics.ObserveQuery("song", method)
+
+	return ctx, func() {
+		done()
+		span.End()
+	}
+}
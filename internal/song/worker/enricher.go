@@ -0,0 +1,197 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"musiclib/config"
+	"musiclib/internal/models"
+	"musiclib/internal/musicinfo"
+	"musiclib/internal/song"
+	"musiclib/pkg/audit"
+	"musiclib/pkg/logger"
+	"sync"
+	"time"
+)
+
+const (
+	defaultEnrichmentWorkers  = 1
+	defaultEnrichmentInterval = 2 * time.Second
+	defaultMaxAttempts        = 8
+	defaultRetryDelay         = 30 * time.Second
+	defaultMaxRetryDelay      = 30 * time.Minute
+	defaultLeaseTimeout       = 2 * time.Minute
+)
+
+// SourceEnrichment marks changes made by the enrichment workers in the song history
+const SourceEnrichment = "enrichment"
+
+// Enricher fills in the music API details of songs that were accepted without them,
+// several enrichers can share the queue
+type Enricher struct {
+	songRepo      song.Repository
+	musicInfo     musicinfo.Provider
+	logger        logger.Logger
+	workers       int
+	interval      time.Duration
+	maxAttempts   int
+	retryDelay    time.Duration
+	maxRetryDelay time.Duration
+	lease         time.Duration
+}
+
+// NewEnricher Enricher constructor, zero config values fall back to the defaults
+func NewEnricher(cfg config.EnrichmentConfig, songRepo song.Repository, musicInfo musicinfo.Provider, logger logger.Logger) *Enricher {
+	e := &Enricher{
+		songRepo:      songRepo,
+		musicInfo:     musicInfo,
+		logger:        logger,
+		workers:       cfg.Workers,
+		interval:      cfg.PollInterval,
+		maxAttempts:   cfg.MaxAttempts,
+		retryDelay:    cfg.RetryDelay,
+		maxRetryDelay: cfg.MaxRetryDelay,
+		lease:         cfg.LeaseTimeout,
+	}
+	if e.workers <= 0 {
+		e.workers = defaultEnrichmentWorkers
+	}
+	if e.interval <= 0 {
+		e.interval = defaultEnrichmentInterval
+	}
+	if e.maxAttempts <= 0 {
+		e.maxAttempts = defaultMaxAttempts
+	}
+	if e.retryDelay <= 0 {
+		e.retryDelay = defaultRetryDelay
+	}
+	if e.maxRetryDelay <= 0 {
+		e.maxRetryDelay = defaultMaxRetryDelay
+	}
+	if e.lease <= 0 {
+		e.lease = defaultLeaseTimeout
+	}
+	return e
+}
+
+// Run starts the workers and waits for them to stop once ctx is canceled
+func (e *Enricher) Run(ctx context.Context) {
+	e.logger.Infof("Song enricher started, workers: %d, poll interval: %s", e.workers, e.interval)
+
+	var wg sync.WaitGroup
+	for i := 0; i < e.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.work(ctx)
+		}()
+	}
+	wg.Wait()
+
+	e.logger.Info("Song enricher stopped")
+}
+
+func (e *Enricher) work(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain enriches songs one after another while jobs are due
+func (e *Enricher) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := e.songRepo.ClaimEnrichmentJob(ctx, e.lease)
+		if errors.Is(err, song.ErrNoEnrichmentJobs) {
+			return
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				e.logger.Errorf("Failed to claim enrichment job: %v", err)
+			}
+			return
+		}
+		e.enrich(ctx, job)
+	}
+}
+
+func (e *Enricher) enrich(ctx context.Context, job *models.EnrichmentJob) {
+	ctx = audit.WithSource(ctx, SourceEnrichment)
+
+	detail, err := e.fetch(ctx, job)
+	if err == nil {
+		_, err = e.songRepo.CompleteEnrichment(ctx, job.SongID, detail)
+		if err == nil {
+			e.logger.Debug("Song enriched", "id", job.SongID)
+			return
+		}
+		if errors.Is(err, song.ErrNotFound) {
+			// Deleted while the details were fetched, the job is picked up again if the song is restored
+			return
+		}
+	}
+	if ctx.Err() != nil {
+		// Shutting down, the lease runs out and another worker retries the job
+		return
+	}
+
+	// The API knowing nothing of the song won't change, anything else may
+	permanent := errors.Is(err, musicinfo.ErrNotFound) || errors.Is(err, musicinfo.ErrInvalidRequest)
+	if permanent || job.Attempts >= e.maxAttempts {
+		e.logger.Warnw("Song enrichment failed", "id", job.SongID, "attempts", job.Attempts, "error", err)
+		if err := e.songRepo.FailEnrichment(ctx, job.SongID, err.Error()); err != nil {
+			e.logger.Errorf("Failed to mark song %d enrichment failed: %v", job.SongID, err)
+		}
+		return
+	}
+
+	retryAt := time.Now().Add(e.backoff(job.Attempts))
+	e.logger.Debug("Song enrichment will be retried", "id", job.SongID, "attempts", job.Attempts, "retryAt", retryAt, "error", err)
+	if err := e.songRepo.RescheduleEnrichment(ctx, job.SongID, err.Error(), retryAt); err != nil {
+		e.logger.Errorf("Failed to reschedule song %d enrichment: %v", job.SongID, err)
+	}
+}
+
+// fetch asks the music API for the song and checks the details can be stored
+func (e *Enricher) fetch(ctx context.Context, job *models.EnrichmentJob) (*models.Song, error) {
+	detail, err := e.musicInfo.GetSongDetail(ctx, job.Group, job.Song)
+	if err != nil {
+		return nil, err
+	}
+	if detail.ReleaseDate == "" && detail.Text == "" && detail.Link == "" {
+		return nil, fmt.Errorf("%w: no song details", musicinfo.ErrBadResponse)
+	}
+
+	result := &models.Song{
+		Text: detail.Text,
+		Link: detail.Link,
+	}
+	if detail.ReleaseDate != "" {
+		releaseDate, err := models.ParseReleaseDate(detail.ReleaseDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid release date: %v", musicinfo.ErrBadResponse, err)
+		}
+		result.ReleaseDate = releaseDate
+	}
+	return result, nil
+}
+
+// backoff doubles the retry delay with every attempt made
+func (e *Enricher) backoff(attempts int) time.Duration {
+	delay := e.retryDelay
+	for i := 1; i < attempts && delay < e.maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > e.maxRetryDelay {
+		delay = e.maxRetryDelay
+	}
+	return delay
+}
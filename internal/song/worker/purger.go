@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"context"
+	"musiclib/config"
+	"musiclib/internal/song"
+	"musiclib/pkg/logger"
+	"time"
+)
+
+const (
+	defaultRetention     = 30 * 24 * time.Hour
+	defaultPurgeInterval = time.Hour
+)
+
+// TrashPurger permanently removes songs that stayed in the trash longer than the retention period
+type TrashPurger struct {
+	songRepo  song.Repository
+	logger    logger.Logger
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewTrashPurger TrashPurger constructor, zero config values fall back to the defaults
+func NewTrashPurger(cfg config.TrashConfig, songRepo song.Repository, logger logger.Logger) *TrashPurger {
+	p := &TrashPurger{
+		songRepo:  songRepo,
+		logger:    logger,
+		retention: cfg.Retention,
+		interval:  cfg.PurgeInterval,
+	}
+	if p.retention <= 0 {
+		p.retention = defaultRetention
+	}
+	if p.interval <= 0 {
+		p.interval = defaultPurgeInterval
+	}
+	return p
+}
+
+// Run purges the trash once right away and then on every interval until ctx is canceled
+func (p *TrashPurger) Run(ctx context.Context) {
+	p.logger.Infof("Trash purger started, retention: %s, interval: %s", p.retention, p.interval)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			p.logger.Info("Trash purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *TrashPurger) purge(ctx context.Context) {
+	purged, err := p.songRepo.PurgeDeleted(ctx, time.Now().Add(-p.retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			p.logger.Errorf("Failed to purge deleted songs: %v", err)
+		}
+	} else if purged > 0 {
+		p.logger.Infof("Purged %d deleted songs", purged)
+	}
+
+	// Expired idempotency keys are cleaned up on the same schedule
+	expired, err := p.songRepo.PurgeIdempotencyKeys(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			p.logger.Errorf("Failed to purge idempotency keys: %v", err)
+		}
+		return
+	}
+	if expired > 0 {
+		p.logger.Infof("Purged %d expired idempotency keys", expired)
+	}
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"musiclib/internal/song"
+	"musiclib/pkg/logger"
+)
+
+// stanzaBackfillBatch is how many songs are split per transaction
+const stanzaBackfillBatch = 100
+
+// StanzaBackfill rebuilds the stanzas of the songs queued by the stanza backfill migration,
+// several instances can share the queue
+type StanzaBackfill struct {
+	songRepo song.Repository
+	logger   logger.Logger
+}
+
+// NewStanzaBackfill StanzaBackfill constructor
+func NewStanzaBackfill(songRepo song.Repository, logger logger.Logger) *StanzaBackfill {
+	return &StanzaBackfill{songRepo: songRepo, logger: logger}
+}
+
+// Run splits the queued songs batch by batch and returns once the queue is empty or ctx is canceled
+func (b *StanzaBackfill) Run(ctx context.Context) {
+	total := 0
+	for ctx.Err() == nil {
+		done, err := b.songRepo.ResplitStanzas(ctx, stanzaBackfillBatch)
+		if err != nil {
+			if ctx.Err() == nil {
+				b.logger.Errorf("Failed to rebuild song stanzas: %v", err)
+			}
+			return
+		}
+		if done == 0 {
+			break
+		}
+		total += done
+	}
+
+	if total > 0 {
+		b.logger.Infof("Rebuilt the stanzas of %d songs", total)
+	}
+}
@@ -0,0 +1,17 @@
+package tag
+
+import (
+	"net/http"
+)
+
+// Tag HTTP Handlers interface
+type Handlers interface {
+	GetList(w http.ResponseWriter, r *http.Request)
+	GetByID(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	GetSongTags(w http.ResponseWriter, r *http.Request)
+	Attach(w http.ResponseWriter, r *http.Request)
+	Detach(w http.ResponseWriter, r *http.Request)
+}
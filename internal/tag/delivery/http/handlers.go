@@ -0,0 +1,367 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"musiclib/config"
+	"musiclib/internal/models"
+	"musiclib/internal/tag"
+	"musiclib/pkg/logger"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+)
+
+const defaultLimit = "10"
+const defaultOffset = "0"
+
+// Tag handlers
+type tagHandlers struct {
+	cfg     *config.Config
+	tagRepo tag.Repository
+	logger  logger.Logger
+}
+
+// NewTagHandlers Tag handlers constructor
+func NewTagHandlers(cfg *config.Config, logger logger.Logger, repo tag.Repository) *tagHandlers {
+	return &tagHandlers{cfg: cfg, logger: logger, tagRepo: repo}
+}
+
+// @Summary     List tags
+// @Description Get paginated list of tags ordered by name, with the number of songs carrying each tag
+// @Tags        tags
+// @Accept      json
+// @Produce     json
+// @Param       kind query string false "Only tags of this kind: genre or label"
+// @Param       limit query int false "Number of items to return (default: 10)"
+// @Param       offset query int false "Number of items to skip (default: 0)"
+// @Success     200 {array} models.Tag
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     500 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /tags [get]
+func (h *tagHandlers) GetList(w http.ResponseWriter, r *http.Request) {
+	kind := r.URL.Query().Get("kind")
+	limit := r.URL.Query().Get("limit")
+	offset := r.URL.Query().Get("offset")
+
+	if kind != "" && !models.IsValidTagKind(kind) {
+		http.Error(w, "Invalid kind value", http.StatusBadRequest)
+		return
+	}
+	if limit == "" {
+		limit = defaultLimit
+	}
+	if offset == "" {
+		offset = defaultOffset
+	}
+
+	limitInt, err := strconv.Atoi(limit)
+	if err != nil {
+		http.Error(w, "Invalid limit value", http.StatusBadRequest)
+		return
+	}
+	offsetInt, err := strconv.Atoi(offset)
+	if err != nil {
+		http.Error(w, "Invalid offset value", http.StatusBadRequest)
+		return
+	}
+
+	tags, err := h.tagRepo.GetList(r.Context(), kind, limitInt, offsetInt)
+	if err != nil {
+		h.logger.Error("Error getting tags from repository", err)
+		http.Error(w, "Error getting tags", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tags)
+}
+
+// @Summary     Get tag
+// @Description Get a tag by ID
+// @Tags        tags
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Tag ID"
+// @Success     200 {object} models.Tag
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /tags/{id} [get]
+func (h *tagHandlers) GetByID(w http.ResponseWriter, r *http.Request) {
+	tagID, ok := parseTagID(w, r)
+	if !ok {
+		return
+	}
+
+	t, err := h.tagRepo.GetByID(r.Context(), tagID)
+	if err != nil {
+		h.handleError(w, err, "Error getting tag")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, t)
+}
+
+// @Summary     Create tag
+// @Description Create a new tag, kind defaults to label
+// @Tags        tags
+// @Accept      json
+// @Produce     json
+// @Param       request body models.TagRequest true "Tag request"
+// @Success     201 {object} models.Tag
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     409 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /tags [post]
+func (h *tagHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeTagRequest(w, r)
+	if !ok {
+		return
+	}
+
+	created, err := h.tagRepo.Create(r.Context(), &models.Tag{
+		Name: req.Name,
+		Kind: req.Kind,
+	})
+	if err != nil {
+		h.handleError(w, err, "Error creating tag")
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, created)
+}
+
+// @Summary     Update tag
+// @Description Rename a tag or change its kind, songs keep the tag
+// @Tags        tags
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Tag ID"
+// @Param       request body models.TagRequest true "Tag request"
+// @Success     200 {object} models.Tag
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Failure     409 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /tags/{id} [put]
+func (h *tagHandlers) Update(w http.ResponseWriter, r *http.Request) {
+	tagID, ok := parseTagID(w, r)
+	if !ok {
+		return
+	}
+
+	req, ok := decodeTagRequest(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := h.tagRepo.Update(r.Context(), &models.Tag{
+		ID:   tagID,
+		Name: req.Name,
+		Kind: req.Kind,
+	})
+	if err != nil {
+		h.handleError(w, err, "Error updating tag")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, updated)
+}
+
+// @Summary     Delete tag
+// @Description Delete a tag and detach it from all songs
+// @Tags        tags
+// @Accept      json
+// @Produce     plain
+// @Param       id path int true "Tag ID"
+// @Success     200 {string} string "Tag deleted successfully"
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /tags/{id} [delete]
+func (h *tagHandlers) Delete(w http.ResponseWriter, r *http.Request) {
+	tagID, ok := parseTagID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.tagRepo.Delete(r.Context(), tagID); err != nil {
+		h.handleError(w, err, "Error deleting tag")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Tag deleted successfully"))
+}
+
+// @Summary     List song tags
+// @Description Get the tags attached to a song, genres first
+// @Tags        tags
+// @Accept      json
+// @Produce     json
+// @Param       songId path int true "Song ID"
+// @Success     200 {array} models.Tag
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/{songId}/tags [get]
+func (h *tagHandlers) GetSongTags(w http.ResponseWriter, r *http.Request) {
+	songID, ok := parseSongID(w, r)
+	if !ok {
+		return
+	}
+
+	tags, err := h.tagRepo.GetSongTags(r.Context(), songID)
+	if err != nil {
+		h.handleError(w, err, "Error getting song tags")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tags)
+}
+
+// @Summary     Attach tag
+// @Description Attach a tag to a song, attaching a tag the song already has is a no-op
+// @Tags        tags
+// @Accept      json
+// @Produce     plain
+// @Param       songId path int true "Song ID"
+// @Param       id path int true "Tag ID"
+// @Success     204
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/{songId}/tags/{id} [put]
+func (h *tagHandlers) Attach(w http.ResponseWriter, r *http.Request) {
+	songID, ok := parseSongID(w, r)
+	if !ok {
+		return
+	}
+	tagID, ok := parseTagID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.tagRepo.Attach(r.Context(), songID, tagID); err != nil {
+		h.handleError(w, err, "Error attaching tag")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary     Detach tag
+// @Description Remove a tag from a song
+// @Tags        tags
+// @Accept      json
+// @Produce     plain
+// @Param       songId path int true "Song ID"
+// @Param       id path int true "Tag ID"
+// @Success     204
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     404 {object} models.ErrorResponse
+// @Security    BearerAuth
+// @Security    ApiKeyAuth
+// @Router      /songs/{songId}/tags/{id} [delete]
+func (h *tagHandlers) Detach(w http.ResponseWriter, r *http.Request) {
+	songID, ok := parseSongID(w, r)
+	if !ok {
+		return
+	}
+	tagID, ok := parseTagID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.tagRepo.Detach(r.Context(), songID, tagID); err != nil {
+		h.handleError(w, err, "Error detaching tag")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleError maps repository errors to HTTP responses
+func (h *tagHandlers) handleError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, tag.ErrNotFound):
+		http.Error(w, "Tag not found", http.StatusNotFound)
+	case errors.Is(err, tag.ErrSongNotFound), errors.Is(err, tag.ErrNotAttached):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, tag.ErrAlreadyExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		h.logger.Error(message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+func (h *tagHandlers) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
+
+// decodeTagRequest reads and validates the tag body, an empty kind becomes label
+func decodeTagRequest(w http.ResponseWriter, r *http.Request) (models.TagRequest, bool) {
+	var req models.TagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return req, false
+	}
+
+	req.Name = models.CleanTagName(req.Name)
+	switch {
+	case req.Name == "":
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return req, false
+	case utf8.RuneCountInString(req.Name) > models.MaxTagNameLength:
+		http.Error(w, "Name is too long", http.StatusBadRequest)
+		return req, false
+	case strings.Contains(req.Name, ","):
+		// Commas separate tags in the song list filters
+		http.Error(w, "Name must not contain commas", http.StatusBadRequest)
+		return req, false
+	}
+
+	if req.Kind == "" {
+		req.Kind = models.TagKindLabel
+	}
+	if !models.IsValidTagKind(req.Kind) {
+		http.Error(w, "Invalid kind value", http.StatusBadRequest)
+		return req, false
+	}
+
+	return req, true
+}
+
+func parseTagID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	tagID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tag ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return tagID, true
+}
+
+func parseSongID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	songID, err := strconv.Atoi(mux.Vars(r)["songId"])
+	if err != nil {
+		http.Error(w, "Invalid song ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return songID, true
+}
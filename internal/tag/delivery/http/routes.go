@@ -0,0 +1,23 @@
+package http
+
+import (
+	"musiclib/internal/tag"
+
+	"github.com/gorilla/mux"
+)
+
+// Map tags routes
+func MapTagRoutes(tagsGroup *mux.Router, h tag.Handlers) {
+	tagsGroup.HandleFunc("", h.GetList).Methods("GET")
+	tagsGroup.HandleFunc("", h.Create).Methods("POST")
+	tagsGroup.HandleFunc("/{id:[0-9]+}", h.GetByID).Methods("GET")
+	tagsGroup.HandleFunc("/{id:[0-9]+}", h.Update).Methods("PUT")
+	tagsGroup.HandleFunc("/{id:[0-9]+}", h.Delete).Methods("DELETE")
+}
+
+// Map the tag routes nested under a song
+func MapSongTagRoutes(songsGroup *mux.Router, h tag.Handlers) {
+	songsGroup.HandleFunc("/{songId:[0-9]+}/tags", h.GetSongTags).Methods("GET")
+	songsGroup.HandleFunc("/{songId:[0-9]+}/tags/{id:[0-9]+}", h.Attach).Methods("PUT")
+	songsGroup.HandleFunc("/{songId:[0-9]+}/tags/{id:[0-9]+}", h.Detach).Methods("DELETE")
+}
@@ -0,0 +1,10 @@
+package tag
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("tag not found")
+	ErrAlreadyExists = errors.New("tag with this name already exists")
+	ErrSongNotFound  = errors.New("song not found")
+	ErrNotAttached   = errors.New("tag is not attached to the song")
+)
@@ -0,0 +1,18 @@
+package tag
+
+import (
+	"context"
+	"musiclib/internal/models"
+)
+
+// Repository interface
+type Repository interface {
+	GetList(ctx context.Context, kind string, limit int, offset int) ([]models.Tag, error)
+	GetByID(ctx context.Context, id int) (*models.Tag, error)
+	Create(ctx context.Context, tag *models.Tag) (*models.Tag, error)
+	Update(ctx context.Context, tag *models.Tag) (*models.Tag, error)
+	Delete(ctx context.Context, id int) error
+	GetSongTags(ctx context.Context, songID int) ([]models.Tag, error)
+	Attach(ctx context.Context, songID int, tagID int) error
+	Detach(ctx context.Context, songID int, tagID int) error
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"musiclib/internal/models"
+	"musiclib/internal/tag"
+	"musiclib/pkg/db/postgres"
+	"musiclib/pkg/logger"
+	"musiclib/pkg/metrics"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type tagRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+func NewTagRepository(db *sqlx.DB, logger logger.Logger) *tagRepository {
+	return &tagRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetList returns tags ordered by name, an empty kind returns tags of every kind
+func (r *tagRepository) GetList(ctx context.Context, kind string, limit int, offset int) ([]models.Tag, error) {
+	defer metrics.ObserveQuery("tag", "GetList")()
+	r.logger.Debug("Starting GetList in tag repository", "kind", kind, "limit", limit, "offset", offset)
+
+	tags := make([]models.Tag, 0)
+	if err := r.db.SelectContext(ctx, &tags, getTags, kind, limit, offset); err != nil {
+		r.logger.Debug("Failed to get tags", "error", err)
+		return nil, fmt.Errorf("failed to get tags list: %w", err)
+	}
+
+	r.logger.Debug("Successfully retrieved tags", "count", len(tags))
+	return tags, nil
+}
+
+func (r *tagRepository) GetByID(ctx context.Context, id int) (*models.Tag, error) {
+	defer metrics.ObserveQuery("tag", "GetByID")()
+	r.logger.Debug("Starting GetByID in tag repository", "id", id)
+
+	var t models.Tag
+	if err := r.db.GetContext(ctx, &t, getTagByID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, tag.ErrNotFound
+		}
+		r.logger.Debug("Failed to get tag", "error", err, "id", id)
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	return &t, nil
+}
+
+func (r *tagRepository) Create(ctx context.Context, t *models.Tag) (*models.Tag, error) {
+	defer metrics.ObserveQuery("tag", "Create")()
+	r.logger.Debug("Starting Create in tag repository", "name", t.Name)
+
+	var created models.Tag
+	err := r.db.GetContext(ctx, &created, createTag,
+		models.CleanTagName(t.Name),
+		models.NormalizeTagName(t.Name),
+		t.Kind,
+	)
+	if err != nil {
+		if postgres.IsUniqueViolation(err) {
+			return nil, tag.ErrAlreadyExists
+		}
+		r.logger.Debug("Failed to create tag", "error", err)
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	r.logger.Debug("Successfully created tag", "id", created.ID)
+	return &created, nil
+}
+
+// Update renames the tag or changes its kind
+func (r *tagRepository) Update(ctx context.Context, t *models.Tag) (*models.Tag, error) {
+	defer metrics.ObserveQuery("tag", "Update")()
+	r.logger.Debug("Starting Update in tag repository", "id", t.ID, "name", t.Name)
+
+	var updated models.Tag
+	err := r.db.GetContext(ctx, &updated, updateTag,
+		models.CleanTagName(t.Name),
+		models.NormalizeTagName(t.Name),
+		t.Kind,
+		t.ID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, tag.ErrNotFound
+		}
+		if postgres.IsUniqueViolation(err) {
+			return nil, tag.ErrAlreadyExists
+		}
+		r.logger.Debug("Failed to update tag", "error", err, "id", t.ID)
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	r.logger.Debug("Successfully updated tag", "id", updated.ID)
+	return &updated, nil
+}
+
+// Delete removes the tag, it is detached from all songs
+func (r *tagRepository) Delete(ctx context.Context, id int) error {
+	defer metrics.ObserveQuery("tag", "Delete")()
+	r.logger.Debug("Starting Delete in tag repository", "id", id)
+
+	result, err := r.db.ExecContext(ctx, deleteTag, id)
+	if err != nil {
+		r.logger.Debug("Failed to delete tag", "error", err, "id", id)
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return tag.ErrNotFound
+	}
+
+	r.logger.Debug("Successfully deleted tag", "id", id)
+	return nil
+}
+
+// GetSongTags returns the tags of a song, genres first
+func (r *tagRepository) GetSongTags(ctx context.Context, songID int) ([]models.Tag, error) {
+	defer metrics.ObserveQuery("tag", "GetSongTags")()
+	r.logger.Debug("Starting GetSongTags in tag repository", "songID", songID)
+
+	if err := r.checkSong(ctx, songID); err != nil {
+		return nil, err
+	}
+
+	tags := make([]models.Tag, 0)
+	if err := r.db.SelectContext(ctx, &tags, getSongTags, songID); err != nil {
+		r.logger.Debug("Failed to get song tags", "error", err, "songID", songID)
+		return nil, fmt.Errorf("failed to get song tags: %w", err)
+	}
+	return tags, nil
+}
+
+// Attach adds the tag to the song, attaching it again is not an error
+func (r *tagRepository) Attach(ctx context.Context, songID int, tagID int) error {
+	defer metrics.ObserveQuery("tag", "Attach")()
+	r.logger.Debug("Starting Attach in tag repository", "songID", songID, "tagID", tagID)
+
+	if err := r.checkSong(ctx, songID); err != nil {
+		return err
+	}
+
+	if _, err := r.db.ExecContext(ctx, attachTag, songID, tagID); err != nil {
+		if postgres.IsForeignKeyViolation(err) {
+			return tag.ErrNotFound
+		}
+		r.logger.Debug("Failed to attach tag", "error", err)
+		return fmt.Errorf("failed to attach tag: %w", err)
+	}
+	return nil
+}
+
+func (r *tagRepository) Detach(ctx context.Context, songID int, tagID int) error {
+	defer metrics.ObserveQuery("tag", "Detach")()
+	r.logger.Debug("Starting Detach in tag repository", "songID", songID, "tagID", tagID)
+
+	result, err := r.db.ExecContext(ctx, detachTag, songID, tagID)
+	if err != nil {
+		r.logger.Debug("Failed to detach tag", "error", err)
+		return fmt.Errorf("failed to detach tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return tag.ErrNotAttached
+	}
+	return nil
+}
+
+func (r *tagRepository) checkSong(ctx context.Context, songID int) error {
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, songExists, songID); err != nil {
+		return fmt.Errorf("failed to check song: %w", err)
+	}
+	if !exists {
+		return tag.ErrSongNotFound
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+const tagColumns = `t.id, t.name, t.kind, t.created_at, t.updated_at,
+    (SELECT COUNT(*) FROM song_tags st JOIN songs s ON s.id = st.song_id
+     WHERE st.tag_id = t.id AND s.deleted_at IS NULL) AS songs_count`
+
+const getTags = `
+    SELECT ` + tagColumns + `
+    FROM tags t
+    WHERE ($1 = '' OR t.kind = $1)
+    ORDER BY t.name, t.id
+    LIMIT $2 OFFSET $3`
+
+const getTagByID = `SELECT ` + tagColumns + ` FROM tags t WHERE t.id = $1`
+
+const createTag = `
+    INSERT INTO tags (name, normalized_name, kind)
+    VALUES ($1, $2, $3)
+    RETURNING id, name, kind, created_at, updated_at`
+
+const updateTag = `
+    UPDATE tags
+    SET name = $1,
+        normalized_name = $2,
+        kind = $3,
+        updated_at = NOW()
+    WHERE id = $4
+    RETURNING id, name, kind, created_at, updated_at`
+
+const deleteTag = `DELETE FROM tags WHERE id = $1`
+
+const getSongTags = `
+    SELECT ` + tagColumns + `
+    FROM tags t
+    JOIN song_tags st ON st.tag_id = t.id
+    WHERE st.song_id = $1
+    ORDER BY t.kind, t.name`
+
+const songExists = `SELECT EXISTS (SELECT 1 FROM songs WHERE id = $1 AND deleted_at IS NULL)`
+
+const attachTag = `
+    INSERT INTO song_tags (song_id, tag_id)
+    VALUES ($1, $2)
+    ON CONFLICT DO NOTHING`
+
+const detachTag = `DELETE FROM song_tags WHERE song_id = $1 AND tag_id = $2`
@@ -0,0 +1,10 @@
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS group_name VARCHAR(255);
+
+UPDATE songs
+SET group_name = artists.name
+FROM artists
+WHERE artists.id = songs.artist_id;
+
+DROP INDEX IF EXISTS idx_songs_artist_id;
+ALTER TABLE songs DROP COLUMN IF EXISTS artist_id;
+DROP TABLE IF EXISTS artists;
@@ -0,0 +1,32 @@
+CREATE TABLE artists
+(
+    id              SERIAL PRIMARY KEY,
+    name            VARCHAR(255) NOT NULL,
+    normalized_name VARCHAR(255) NOT NULL UNIQUE,
+    description     TEXT         NOT NULL DEFAULT '',
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Backfill artists from the free-text group names, keeping the first spelling seen
+INSERT INTO artists (name, normalized_name)
+SELECT DISTINCT ON (normalized_name) name, normalized_name
+FROM (
+    SELECT regexp_replace(btrim(group_name), '\s+', ' ', 'g')        AS name,
+           lower(regexp_replace(btrim(group_name), '\s+', ' ', 'g')) AS normalized_name,
+           id
+    FROM songs
+    WHERE btrim(COALESCE(group_name, '')) <> ''
+) AS groups
+ORDER BY normalized_name, id;
+
+ALTER TABLE songs ADD COLUMN artist_id INTEGER REFERENCES artists (id) ON DELETE RESTRICT;
+
+UPDATE songs
+SET artist_id = artists.id
+FROM artists
+WHERE artists.normalized_name = lower(regexp_replace(btrim(songs.group_name), '\s+', ' ', 'g'));
+
+CREATE INDEX idx_songs_artist_id ON songs (artist_id);
+
+ALTER TABLE songs DROP COLUMN group_name;
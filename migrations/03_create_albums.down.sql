@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_songs_album_track;
+
+ALTER TABLE songs
+    DROP CONSTRAINT IF EXISTS songs_track_position_check,
+    DROP COLUMN IF EXISTS track_number,
+    DROP COLUMN IF EXISTS disc_number,
+    DROP COLUMN IF EXISTS album_id;
+
+DROP TABLE IF EXISTS albums;
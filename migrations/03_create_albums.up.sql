@@ -0,0 +1,23 @@
+CREATE TABLE albums
+(
+    id           SERIAL PRIMARY KEY,
+    title        VARCHAR(255) NOT NULL,
+    artist_id    INTEGER      NOT NULL REFERENCES artists (id) ON DELETE RESTRICT,
+    release_date DATE,
+    type         VARCHAR(16)  NOT NULL DEFAULT 'LP'
+        CHECK (type IN ('LP', 'EP', 'single', 'compilation')),
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_albums_artist_id ON albums (artist_id);
+
+ALTER TABLE songs
+    ADD COLUMN album_id     INTEGER REFERENCES albums (id) ON DELETE SET NULL,
+    ADD COLUMN disc_number  SMALLINT,
+    ADD COLUMN track_number SMALLINT,
+    ADD CONSTRAINT songs_track_position_check
+        CHECK (album_id IS NULL OR (disc_number > 0 AND track_number > 0));
+
+CREATE UNIQUE INDEX idx_songs_album_track ON songs (album_id, disc_number, track_number)
+    WHERE album_id IS NOT NULL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_songs_search_vector;
+DROP TRIGGER IF EXISTS artists_search_vector_trigger ON artists;
+DROP TRIGGER IF EXISTS songs_search_vector_trigger ON songs;
+DROP FUNCTION IF EXISTS artists_search_vector_update();
+DROP FUNCTION IF EXISTS songs_search_vector_update();
+ALTER TABLE songs DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS songs_search_vector(TEXT, TEXT, TEXT);
@@ -0,0 +1,55 @@
+-- Lyrics are stored with escaped newlines, so they are unescaped before indexing.
+-- Both configurations are indexed because the catalog mixes Russian and English songs.
+CREATE FUNCTION songs_search_vector(title TEXT, lyrics TEXT, artist TEXT) RETURNS tsvector
+    LANGUAGE sql
+    IMMUTABLE AS
+$$
+SELECT setweight(to_tsvector('english', COALESCE(title, '')), 'A')
+    || setweight(to_tsvector('russian', COALESCE(title, '')), 'A')
+    || setweight(to_tsvector('english', COALESCE(artist, '')), 'B')
+    || setweight(to_tsvector('russian', COALESCE(artist, '')), 'B')
+    || setweight(to_tsvector('english', replace(COALESCE(lyrics, ''), '\n', E'\n')), 'C')
+    || setweight(to_tsvector('russian', replace(COALESCE(lyrics, ''), '\n', E'\n')), 'C')
+$$;
+
+ALTER TABLE songs ADD COLUMN search_vector tsvector;
+
+CREATE FUNCTION songs_search_vector_update() RETURNS trigger
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    NEW.search_vector := songs_search_vector(
+            NEW.song, NEW.text, (SELECT name FROM artists WHERE id = NEW.artist_id));
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER songs_search_vector_trigger
+    BEFORE INSERT OR UPDATE OF song, text, artist_id
+    ON songs
+    FOR EACH ROW
+EXECUTE FUNCTION songs_search_vector_update();
+
+-- Keep the artist part of the vector in sync when an artist is renamed
+CREATE FUNCTION artists_search_vector_update() RETURNS trigger
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    UPDATE songs
+    SET search_vector = songs_search_vector(song, text, NEW.name)
+    WHERE artist_id = NEW.id;
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER artists_search_vector_trigger
+    AFTER UPDATE OF name
+    ON artists
+    FOR EACH ROW
+    WHEN (OLD.name IS DISTINCT FROM NEW.name)
+EXECUTE FUNCTION artists_search_vector_update();
+
+UPDATE songs
+SET search_vector = songs_search_vector(song, text, (SELECT name FROM artists WHERE id = songs.artist_id));
+
+CREATE INDEX idx_songs_search_vector ON songs USING GIN (search_vector);
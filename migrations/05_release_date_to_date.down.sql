@@ -0,0 +1,19 @@
+ALTER TABLE albums
+    DROP CONSTRAINT IF EXISTS albums_release_date_precision_check,
+    DROP COLUMN IF EXISTS release_date_precision;
+
+DROP INDEX IF EXISTS idx_songs_release_date;
+
+ALTER TABLE songs
+    DROP CONSTRAINT IF EXISTS songs_release_date_precision_check,
+    ADD COLUMN release_date_text VARCHAR(12);
+
+UPDATE songs
+SET release_date_text = format_release_date(release_date, release_date_precision);
+
+ALTER TABLE songs
+    DROP COLUMN release_date,
+    DROP COLUMN release_date_precision;
+ALTER TABLE songs RENAME COLUMN release_date_text TO release_date;
+
+DROP FUNCTION IF EXISTS format_release_date(DATE, VARCHAR);
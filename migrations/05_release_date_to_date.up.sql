@@ -0,0 +1,73 @@
+-- Parses the free-text release dates stored so far. Unparsable values become NULL.
+CREATE FUNCTION pg_temp.parse_release_date(value TEXT, OUT day DATE, OUT date_precision VARCHAR(5))
+    LANGUAGE plpgsql AS
+$$
+DECLARE
+    v TEXT := btrim(COALESCE(value, ''));
+BEGIN
+    IF v ~ '^\d{4}-\d{1,2}-\d{1,2}$' THEN
+        day := to_date(v, 'YYYY-MM-DD'); date_precision := 'day';
+    ELSIF v ~ '^\d{1,2}\.\d{1,2}\.\d{4}$' THEN
+        day := to_date(v, 'DD.MM.YYYY'); date_precision := 'day';
+    ELSIF v ~ '^\d{1,2}/\d{1,2}/\d{4}$' THEN
+        day := to_date(v, 'DD/MM/YYYY'); date_precision := 'day';
+    ELSIF v ~ '^\d{4}/\d{1,2}/\d{1,2}$' THEN
+        day := to_date(v, 'YYYY/MM/DD'); date_precision := 'day';
+    ELSIF v ~ '^\d{4}-\d{1,2}$' THEN
+        day := to_date(v, 'YYYY-MM'); date_precision := 'month';
+    ELSIF v ~ '^\d{1,2}\.\d{4}$' THEN
+        day := to_date(v, 'MM.YYYY'); date_precision := 'month';
+    ELSIF v ~ '^\d{4}$' THEN
+        day := to_date(v, 'YYYY'); date_precision := 'year';
+    ELSIF v <> '' THEN
+        RAISE NOTICE 'release date % could not be parsed and was dropped', quote_literal(v);
+    END IF;
+EXCEPTION
+    WHEN datetime_field_overflow OR invalid_datetime_format THEN
+        RAISE NOTICE 'release date % is out of range and was dropped', quote_literal(v);
+        day := NULL;
+        date_precision := NULL;
+END
+$$;
+
+-- Renders a release date as ISO-8601 with the precision it is known to
+CREATE FUNCTION format_release_date(day DATE, date_precision VARCHAR) RETURNS TEXT
+    LANGUAGE sql
+    IMMUTABLE AS
+$$
+SELECT CASE date_precision
+           WHEN 'year' THEN to_char(day, 'YYYY')
+           WHEN 'month' THEN to_char(day, 'YYYY-MM')
+           ELSE to_char(day, 'YYYY-MM-DD')
+           END
+$$;
+
+ALTER TABLE songs
+    ADD COLUMN release_day            DATE,
+    ADD COLUMN release_date_precision VARCHAR(5);
+
+UPDATE songs
+SET (release_day, release_date_precision) = (
+    SELECT p.day, p.date_precision FROM pg_temp.parse_release_date(songs.release_date) AS p);
+
+ALTER TABLE songs DROP COLUMN release_date;
+ALTER TABLE songs RENAME COLUMN release_day TO release_date;
+
+ALTER TABLE songs
+    ADD CONSTRAINT songs_release_date_precision_check
+        CHECK (release_date_precision IN ('day', 'month', 'year')
+            AND (release_date IS NULL) = (release_date_precision IS NULL));
+
+CREATE INDEX idx_songs_release_date ON songs (release_date);
+
+ALTER TABLE albums
+    ADD COLUMN release_date_precision VARCHAR(5);
+
+UPDATE albums
+SET release_date_precision = 'day'
+WHERE release_date IS NOT NULL;
+
+ALTER TABLE albums
+    ADD CONSTRAINT albums_release_date_precision_check
+        CHECK (release_date_precision IN ('day', 'month', 'year')
+            AND (release_date IS NULL) = (release_date_precision IS NULL));
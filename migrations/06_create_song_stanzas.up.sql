@@ -0,0 +1,11 @@
+CREATE TABLE song_stanzas
+(
+    song_id  INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    type     VARCHAR(16)
+        CHECK (type IN ('verse', 'pre-chorus', 'chorus', 'bridge', 'intro', 'outro', 'hook')),
+    text     TEXT    NOT NULL,
+    PRIMARY KEY (song_id, position)
+);
+
+-- Existing lyrics are split by pkg/lyrics, see 17_resplit_song_stanzas
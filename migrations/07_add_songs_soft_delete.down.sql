@@ -0,0 +1,8 @@
+DELETE FROM songs WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_songs_album_track;
+CREATE UNIQUE INDEX idx_songs_album_track ON songs (album_id, disc_number, track_number)
+    WHERE album_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_songs_deleted_at;
+ALTER TABLE songs DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,8 @@
+ALTER TABLE songs ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_songs_deleted_at ON songs (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Songs in the trash no longer hold their album position
+DROP INDEX idx_songs_album_track;
+CREATE UNIQUE INDEX idx_songs_album_track ON songs (album_id, disc_number, track_number)
+    WHERE album_id IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,43 @@
+-- Revisions outlive the song itself, so there is no foreign key to songs
+CREATE TABLE song_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    song_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    action VARCHAR(16) NOT NULL
+        CHECK (action IN ('create', 'update', 'delete', 'restore', 'hard_delete', 'rollback')),
+    snapshot JSONB NOT NULL,
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    source VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (song_id, revision)
+);
+
+CREATE FUNCTION song_revisions_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'song revisions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER song_revisions_immutable
+    BEFORE UPDATE OR DELETE ON song_revisions
+    FOR EACH ROW EXECUTE FUNCTION song_revisions_immutable();
+
+-- Existing songs start their history with a snapshot of the current state
+INSERT INTO song_revisions (song_id, revision, action, snapshot, source)
+SELECT s.id, 1, CASE WHEN s.deleted_at IS NULL THEN 'create' ELSE 'delete' END,
+       jsonb_strip_nulls(jsonb_build_object(
+           'id', s.id,
+           'artistId', COALESCE(s.artist_id, 0),
+           'group', COALESCE(a.name, ''),
+           'song', COALESCE(s.song, ''),
+           'releaseDate', format_release_date(s.release_date, s.release_date_precision),
+           'text', COALESCE(s.text, ''),
+           'link', COALESCE(s.link, ''),
+           'albumId', s.album_id,
+           'discNumber', s.disc_number,
+           'trackNumber', s.track_number,
+           'deletedAt', s.deleted_at
+       )),
+       'migration'
+FROM songs s
+LEFT JOIN artists a ON a.id = s.artist_id;
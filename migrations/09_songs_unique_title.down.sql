@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS idempotency_keys;
+DROP INDEX IF EXISTS idx_songs_artist_title;
+ALTER TABLE songs DROP COLUMN IF EXISTS normalized_title;
+DROP FUNCTION IF EXISTS normalize_song_title(TEXT);
@@ -0,0 +1,68 @@
+-- Titles are compared case-insensitively with whitespace collapsed, the same way artist names are
+CREATE FUNCTION normalize_song_title(title TEXT) RETURNS TEXT AS $$
+    SELECT lower(regexp_replace(btrim(COALESCE(title, '')), '\s+', ' ', 'g'))
+$$ LANGUAGE SQL IMMUTABLE;
+
+ALTER TABLE songs
+    ADD COLUMN normalized_title TEXT GENERATED ALWAYS AS (normalize_song_title(song)) STORED;
+
+-- Existing duplicates go to the trash, the oldest song of each group is kept. Each trashed song gets
+-- a delete revision like any other delete, so it can be restored and its snapshot outlives the purge.
+DO $$
+DECLARE
+    trashed INTEGER[];
+BEGIN
+    WITH duplicates AS (
+        SELECT id FROM (
+            SELECT id, row_number() OVER (PARTITION BY artist_id, normalized_title ORDER BY id) AS rn
+            FROM songs
+            WHERE deleted_at IS NULL AND artist_id IS NOT NULL
+        ) d
+        WHERE d.rn > 1
+    ), moved AS (
+        UPDATE songs s SET deleted_at = NOW()
+        FROM duplicates d
+        WHERE s.id = d.id
+        RETURNING s.*
+    ), logged AS (
+        INSERT INTO song_revisions (song_id, revision, action, snapshot, source)
+        SELECT m.id,
+               (SELECT COALESCE(MAX(r.revision), 0) + 1 FROM song_revisions r WHERE r.song_id = m.id),
+               'delete',
+               jsonb_strip_nulls(jsonb_build_object(
+                   'id', m.id,
+                   'artistId', COALESCE(m.artist_id, 0),
+                   'group', COALESCE(a.name, ''),
+                   'song', COALESCE(m.song, ''),
+                   'releaseDate', format_release_date(m.release_date, m.release_date_precision),
+                   'text', COALESCE(m.text, ''),
+                   'link', COALESCE(m.link, ''),
+                   'albumId', m.album_id,
+                   'discNumber', m.disc_number,
+                   'trackNumber', m.track_number,
+                   'deletedAt', m.deleted_at
+               )),
+               'migration'
+        FROM moved m
+        LEFT JOIN artists a ON a.id = m.artist_id
+        RETURNING song_id
+    )
+    SELECT array_agg(song_id ORDER BY song_id) INTO trashed FROM logged;
+
+    IF trashed IS NOT NULL THEN
+        RAISE WARNING 'moved % duplicate songs to the trash: %', cardinality(trashed), trashed;
+    END IF;
+END
+$$;
+
+CREATE UNIQUE INDEX idx_songs_artist_title ON songs (artist_id, normalized_title)
+    WHERE deleted_at IS NULL;
+
+CREATE TABLE idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    request_hash CHAR(64) NOT NULL,
+    song_id INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys (created_at);
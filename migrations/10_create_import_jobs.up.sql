@@ -0,0 +1,40 @@
+CREATE TABLE import_jobs (
+    id SERIAL PRIMARY KEY,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    format VARCHAR(8) NOT NULL CHECK (format IN ('csv', 'ndjson')),
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    created_rows INTEGER NOT NULL DEFAULT 0,
+    updated_rows INTEGER NOT NULL DEFAULT 0,
+    skipped_rows INTEGER NOT NULL DEFAULT 0,
+    failed_rows INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_import_jobs_status ON import_jobs (status, id) WHERE status IN ('pending', 'running');
+
+CREATE TABLE import_job_rows (
+    job_id INTEGER NOT NULL REFERENCES import_jobs (id) ON DELETE CASCADE,
+    line INTEGER NOT NULL,
+    group_name VARCHAR(255) NOT NULL DEFAULT '',
+    song VARCHAR(255) NOT NULL DEFAULT '',
+    release_date VARCHAR(32) NOT NULL DEFAULT '',
+    text TEXT NOT NULL DEFAULT '',
+    link VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'done', 'failed')),
+    action VARCHAR(8) NOT NULL DEFAULT ''
+        CHECK (action IN ('', 'create', 'update', 'skip')),
+    song_id INTEGER,
+    error TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (job_id, line)
+);
+
+CREATE INDEX idx_import_job_rows_pending ON import_job_rows (job_id, line) WHERE status = 'pending';
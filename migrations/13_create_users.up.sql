@@ -0,0 +1,24 @@
+CREATE TABLE users
+(
+    id                  SERIAL PRIMARY KEY,
+    username            VARCHAR(64)  NOT NULL,
+    normalized_username VARCHAR(64)  NOT NULL UNIQUE,
+    password_hash       VARCHAR(255) NOT NULL,
+    role                VARCHAR(16)  NOT NULL DEFAULT 'viewer'
+        CHECK (role IN ('viewer', 'editor', 'admin')),
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Only the SHA-256 of a refresh token is stored, a used token is revoked and replaced
+CREATE TABLE refresh_tokens
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INTEGER  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens (user_id);
@@ -0,0 +1,21 @@
+-- Only the SHA-256 of a key is stored, prefix is the start of the key shown to tell keys apart
+CREATE TABLE api_keys
+(
+    id              SERIAL PRIMARY KEY,
+    name            VARCHAR(100) NOT NULL,
+    normalized_name VARCHAR(100) NOT NULL,
+    prefix          VARCHAR(16)  NOT NULL,
+    key_hash        CHAR(64)     NOT NULL UNIQUE,
+    scopes          TEXT[]       NOT NULL
+        CHECK (cardinality(scopes) > 0 AND scopes <@ ARRAY ['songs:read', 'songs:write', 'admin']),
+    created_by      INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMP WITH TIME ZONE,
+    rotated_at      TIMESTAMP WITH TIME ZONE,
+    revoked_at      TIMESTAMP WITH TIME ZONE,
+    last_used_at    TIMESTAMP WITH TIME ZONE,
+    request_count   BIGINT       NOT NULL DEFAULT 0
+);
+
+-- Names of revoked keys can be reused
+CREATE UNIQUE INDEX idx_api_keys_name ON api_keys (normalized_name) WHERE revoked_at IS NULL;
@@ -0,0 +1,15 @@
+ALTER TABLE songs
+    ADD COLUMN enrichment_status VARCHAR(16) NOT NULL DEFAULT 'enriched'
+        CHECK (enrichment_status IN ('pending', 'enriched', 'failed')),
+    ADD COLUMN enrichment_error TEXT NOT NULL DEFAULT '';
+
+-- One row per song still waiting for its music API details, workers claim them with SKIP LOCKED
+CREATE TABLE enrichment_jobs (
+    song_id INTEGER PRIMARY KEY REFERENCES songs (id) ON DELETE CASCADE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_enrichment_jobs_next_attempt ON enrichment_jobs (next_attempt_at);
@@ -0,0 +1,8 @@
+-- Keys reused by several callers cannot share one namespace again
+DELETE FROM idempotency_keys a
+USING idempotency_keys b
+WHERE a.key = b.key AND (a.created_at, a.principal) < (b.created_at, b.principal);
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (key);
+ALTER TABLE idempotency_keys DROP COLUMN principal;
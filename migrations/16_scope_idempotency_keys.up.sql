@@ -0,0 +1,5 @@
+-- Keys are chosen by clients, so each caller gets a namespace of its own
+ALTER TABLE idempotency_keys ADD COLUMN principal VARCHAR(64) NOT NULL DEFAULT '';
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (principal, key);
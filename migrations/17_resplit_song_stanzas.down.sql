@@ -0,0 +1 @@
+DROP TABLE IF EXISTS stanza_backfill;
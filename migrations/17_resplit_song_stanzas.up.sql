@@ -0,0 +1,11 @@
+-- Songs whose stanzas are rebuilt by the stanza backfill worker with pkg/lyrics, so lyrics saved
+-- before stanzas existed are split by the same label rules as lyrics saved later
+CREATE TABLE stanza_backfill
+(
+    song_id INTEGER PRIMARY KEY REFERENCES songs (id) ON DELETE CASCADE
+);
+
+INSERT INTO stanza_backfill (song_id)
+SELECT id
+FROM songs
+WHERE COALESCE(text, '') <> '';
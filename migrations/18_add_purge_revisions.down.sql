@@ -0,0 +1,4 @@
+-- Revisions are immutable, purge revisions already written are kept and skip the check
+ALTER TABLE song_revisions DROP CONSTRAINT song_revisions_action_check;
+ALTER TABLE song_revisions ADD CONSTRAINT song_revisions_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'restore', 'hard_delete', 'rollback')) NOT VALID;
@@ -0,0 +1,4 @@
+-- Purged songs get a last revision holding their snapshot, like permanent deletes do
+ALTER TABLE song_revisions DROP CONSTRAINT song_revisions_action_check;
+ALTER TABLE song_revisions ADD CONSTRAINT song_revisions_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'restore', 'hard_delete', 'rollback', 'purge'));
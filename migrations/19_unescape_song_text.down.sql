@@ -0,0 +1,12 @@
+-- Lyrics keep their real line breaks, which the previous search function reads the same way
+CREATE OR REPLACE FUNCTION songs_search_vector(title TEXT, lyrics TEXT, artist TEXT) RETURNS tsvector
+    LANGUAGE sql
+    IMMUTABLE AS
+$$
+SELECT setweight(to_tsvector('english', COALESCE(title, '')), 'A')
+    || setweight(to_tsvector('russian', COALESCE(title, '')), 'A')
+    || setweight(to_tsvector('english', COALESCE(artist, '')), 'B')
+    || setweight(to_tsvector('russian', COALESCE(artist, '')), 'B')
+    || setweight(to_tsvector('english', replace(COALESCE(lyrics, ''), '\n', E'\n')), 'C')
+    || setweight(to_tsvector('russian', replace(COALESCE(lyrics, ''), '\n', E'\n')), 'C')
+$$;
@@ -0,0 +1,29 @@
+-- Songs added through the API, the enrichment workers and imports used to store line breaks as the two
+-- characters \n, while PUT and PATCH stored real ones. Lyrics are stored with real line breaks only now.
+CREATE OR REPLACE FUNCTION songs_search_vector(title TEXT, lyrics TEXT, artist TEXT) RETURNS tsvector
+    LANGUAGE sql
+    IMMUTABLE AS
+$$
+SELECT setweight(to_tsvector('english', COALESCE(title, '')), 'A')
+    || setweight(to_tsvector('russian', COALESCE(title, '')), 'A')
+    || setweight(to_tsvector('english', COALESCE(artist, '')), 'B')
+    || setweight(to_tsvector('russian', COALESCE(artist, '')), 'B')
+    || setweight(to_tsvector('english', COALESCE(lyrics, '')), 'C')
+    || setweight(to_tsvector('russian', COALESCE(lyrics, '')), 'C')
+$$;
+
+-- The search vector trigger reindexes every changed song, stanzas were split from unescaped text already
+UPDATE songs
+SET text = replace(replace(text, E'\r\n', E'\n'), '\n', E'\n')
+WHERE strpos(text, '\n') > 0 OR strpos(text, E'\r\n') > 0;
+
+-- Snapshots are converted too, so rolling back to an old revision doesn't bring escaped lyrics back.
+-- Only the encoding changes, which is why the immutability trigger is suspended for this statement.
+ALTER TABLE song_revisions DISABLE TRIGGER song_revisions_immutable;
+
+UPDATE song_revisions
+SET snapshot = jsonb_set(snapshot, '{text}',
+                         to_jsonb(replace(replace(snapshot ->> 'text', E'\r\n', E'\n'), '\n', E'\n')))
+WHERE strpos(snapshot ->> 'text', '\n') > 0 OR strpos(snapshot ->> 'text', E'\r\n') > 0;
+
+ALTER TABLE song_revisions ENABLE TRIGGER song_revisions_immutable;
@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL migrations so the binary carries the schema it expects.
+package migrations
+
+import "embed"
+
+// FS holds the up and down migrations in golang-migrate's NN_name.{up,down}.sql layout
+//
+//go:embed *.sql
+var FS embed.FS
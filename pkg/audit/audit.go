@@ -0,0 +1,65 @@
+// Package audit carries the author of a change through the request context.
+package audit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Sources of changes
+const (
+	SourceAPI    = "api"
+	SourceSystem = "system"
+)
+
+// ActorHeader lets API clients tell who is making the change
+const ActorHeader = "X-Actor"
+
+// maxActorLength matches the size of the actor columns
+const maxActorLength = 255
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	sourceKey
+)
+
+// WithActor returns a copy of ctx carrying the actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Actor returns the actor stored in ctx or an empty string
+func Actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
+
+// WithSource returns a copy of ctx carrying the source of the change
+func WithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceKey, source)
+}
+
+// Source returns the source stored in ctx, changes without one are attributed to the system
+func Source(ctx context.Context) string {
+	if source, ok := ctx.Value(sourceKey).(string); ok {
+		return source
+	}
+	return SourceSystem
+}
+
+// Middleware marks requests as API changes made by the actor from the X-Actor header
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := WithSource(r.Context(), SourceAPI)
+		if actor := strings.TrimSpace(r.Header.Get(ActorHeader)); actor != "" {
+			if runes := []rune(actor); len(runes) > maxActorLength {
+				actor = string(runes[:maxActorLength])
+			}
+			ctx = WithActor(ctx, actor)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,133 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+const testCooldown = 20 * time.Millisecond
+
+// step is one call on the breaker: allow expects Allow to succeed or fail with ErrOpen,
+// the other ops record an outcome and wait waits out the cooldown
+type step struct {
+	op    string
+	allow bool
+}
+
+func TestBreaker(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		steps     []step
+	}{
+		{
+			name:      "stays closed below the threshold",
+			threshold: 3,
+			steps: []step{
+				{op: "allow", allow: true}, {op: "failure"},
+				{op: "allow", allow: true}, {op: "failure"},
+				{op: "allow", allow: true},
+			},
+		},
+		{
+			name:      "opens at the threshold",
+			threshold: 2,
+			steps: []step{
+				{op: "allow", allow: true}, {op: "failure"},
+				{op: "allow", allow: true}, {op: "failure"},
+				{op: "allow", allow: false},
+				{op: "allow", allow: false},
+			},
+		},
+		{
+			name:      "success resets the count",
+			threshold: 2,
+			steps: []step{
+				{op: "allow", allow: true}, {op: "failure"},
+				{op: "allow", allow: true}, {op: "success"},
+				{op: "allow", allow: true}, {op: "failure"},
+				{op: "allow", allow: true},
+			},
+		},
+		{
+			name:      "half open lets a single trial through",
+			threshold: 1,
+			steps: []step{
+				{op: "allow", allow: true}, {op: "failure"},
+				{op: "wait"},
+				{op: "allow", allow: true},
+				{op: "allow", allow: false},
+			},
+		},
+		{
+			name:      "successful trial closes",
+			threshold: 1,
+			steps: []step{
+				{op: "allow", allow: true}, {op: "failure"},
+				{op: "wait"},
+				{op: "allow", allow: true}, {op: "success"},
+				{op: "allow", allow: true},
+				{op: "allow", allow: true},
+			},
+		},
+		{
+			name:      "failed trial opens again",
+			threshold: 3,
+			steps: []step{
+				{op: "allow", allow: true}, {op: "failure"},
+				{op: "allow", allow: true}, {op: "failure"},
+				{op: "allow", allow: true}, {op: "failure"},
+				{op: "wait"},
+				{op: "allow", allow: true}, {op: "failure"},
+				{op: "allow", allow: false},
+			},
+		},
+		{
+			name:      "released trial lets another one through",
+			threshold: 1,
+			steps: []step{
+				{op: "allow", allow: true}, {op: "failure"},
+				{op: "wait"},
+				{op: "allow", allow: true}, {op: "release"},
+				{op: "allow", allow: true},
+				{op: "allow", allow: false},
+			},
+		},
+		{
+			name:      "zero threshold never opens",
+			threshold: 0,
+			steps: []step{
+				{op: "allow", allow: true}, {op: "failure"},
+				{op: "allow", allow: true}, {op: "failure"},
+				{op: "allow", allow: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.threshold, testCooldown)
+			for i, s := range tt.steps {
+				switch s.op {
+				case "allow":
+					err := b.Allow()
+					if s.allow && err != nil {
+						t.Fatalf("step %d: Allow() error = %v, want nil", i, err)
+					}
+					if !s.allow && !errors.Is(err, ErrOpen) {
+						t.Fatalf("step %d: Allow() error = %v, want ErrOpen", i, err)
+					}
+				case "success":
+					b.Success()
+				case "failure":
+					b.Failure()
+				case "release":
+					b.Release()
+				case "wait":
+					time.Sleep(testCooldown + 5*time.Millisecond)
+				}
+			}
+		})
+	}
+}
@@ -1,28 +1,36 @@
 package migrations
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 
+	schema "musiclib/migrations"
+
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
+// getVersion reads the table golang-migrate keeps its state in
+const getVersion = `SELECT version, dirty FROM schema_migrations LIMIT 1`
+
 // RunMigrations выполняет миграции базы данных
-func RunMigrations(db *sql.DB, migrationsPath string) error {
+func RunMigrations(db *sql.DB) error {
 	driver, err := postgres.WithInstance(db, &postgres.Config{})
 	if err != nil {
 		return fmt.Errorf("could not create the postgres driver: %v", err)
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		fmt.Sprintf("file://%s", migrationsPath),
-		"postgres",
-		driver,
-	)
+	source, err := iofs.New(schema.FS, ".")
+	if err != nil {
+		return fmt.Errorf("could not read embedded migrations: %v", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
 	if err != nil {
 		return fmt.Errorf("could not create migrate instance: %v", err)
 	}
@@ -34,3 +42,37 @@ func RunMigrations(db *sql.DB, migrationsPath string) error {
 	log.Println("Migrations successfully executed")
 	return nil
 }
+
+// LatestVersion returns the version of the newest embedded migration
+func LatestVersion() (uint, error) {
+	source, err := iofs.New(schema.FS, ".")
+	if err != nil {
+		return 0, fmt.Errorf("could not read embedded migrations: %v", err)
+	}
+	defer source.Close()
+
+	version, err := source.First()
+	if err != nil {
+		return 0, fmt.Errorf("could not read embedded migrations: %v", err)
+	}
+	for {
+		next, err := source.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("could not read embedded migrations: %v", err)
+		}
+		version = next
+	}
+}
+
+// Version returns the migration version the database is at, dirty is set when a migration failed halfway.
+// A database that was never migrated is at version 0
+func Version(ctx context.Context, db *sql.DB) (version uint, dirty bool, err error) {
+	err = db.QueryRowContext(ctx, getVersion).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
@@ -5,7 +5,9 @@ import (
 	"musiclib/config"
 	"time"
 
+	"github.com/XSAM/otelsql"
 	"github.com/jmoiron/sqlx"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 const (
@@ -25,10 +27,19 @@ func NewPsqlDB(c *config.Config) (*sqlx.DB, error) {
 		c.Database.Password,
 	)
 
-	db, err := sqlx.Open("postgres", dataSourceName)
+	// Every statement becomes a span carrying its SQL, child of the span of the calling request
+	sqlDB, err := otelsql.Open("postgres", dataSourceName,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+			OmitRows:             true,
+		}),
+	)
 	if err != nil {
 		return nil, err
 	}
+	db := sqlx.NewDb(sqlDB, "postgres")
 
 	db.SetMaxOpenConns(maxOpenConns)
 	db.SetConnMaxLifetime(connMaxLifetime * time.Second)
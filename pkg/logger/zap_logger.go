@@ -14,10 +14,13 @@ type Logger interface {
 	Debugf(template string, args ...interface{})
 	Info(args ...interface{})
 	Infof(template string, args ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
 	Warn(args ...interface{})
 	Warnf(template string, args ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
 	Error(args ...interface{})
 	Errorf(template string, args ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
 	DPanic(args ...interface{})
 	DPanicf(template string, args ...interface{})
 	Fatal(args ...interface{})
@@ -112,6 +115,11 @@ func (l *apiLogger) Infof(template string, args ...interface{}) {
 	l.sugarLogger.Infof(template, args...)
 }
 
+// Infow logs a message with structured key-value pairs
+func (l *apiLogger) Infow(msg string, keysAndValues ...interface{}) {
+	l.sugarLogger.Infow(msg, keysAndValues...)
+}
+
 func (l *apiLogger) Warn(args ...interface{}) {
 	l.sugarLogger.Warn(args...)
 }
@@ -120,6 +128,11 @@ func (l *apiLogger) Warnf(template string, args ...interface{}) {
 	l.sugarLogger.Warnf(template, args...)
 }
 
+// Warnw logs a message with structured key-value pairs
+func (l *apiLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.sugarLogger.Warnw(msg, keysAndValues...)
+}
+
 func (l *apiLogger) Error(args ...interface{}) {
 	l.sugarLogger.Error(args...)
 }
@@ -128,6 +141,11 @@ func (l *apiLogger) Errorf(template string, args ...interface{}) {
 	l.sugarLogger.Errorf(template, args...)
 }
 
+// Errorw logs a message with structured key-value pairs
+func (l *apiLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.sugarLogger.Errorw(msg, keysAndValues...)
+}
+
 func (l *apiLogger) DPanic(args ...interface{}) {
 	l.sugarLogger.DPanic(args...)
 }
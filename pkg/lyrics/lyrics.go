@@ -0,0 +1,103 @@
+package lyrics
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Stanza types
+const (
+	TypeVerse     = "verse"
+	TypePreChorus = "pre-chorus"
+	TypeChorus    = "chorus"
+	TypeBridge    = "bridge"
+	TypeIntro     = "intro"
+	TypeOutro     = "outro"
+	TypeHook      = "hook"
+)
+
+// labelTypes maps the labels found in lyrics, English and Russian, to stanza types
+var labelTypes = map[string]string{
+	"verse":      TypeVerse,
+	"куплет":     TypeVerse,
+	"pre-chorus": TypePreChorus,
+	"prechorus":  TypePreChorus,
+	"chorus":     TypeChorus,
+	"refrain":    TypeChorus,
+	"припев":     TypeChorus,
+	"bridge":     TypeBridge,
+	"бридж":      TypeBridge,
+	"intro":      TypeIntro,
+	"интро":      TypeIntro,
+	"вступление": TypeIntro,
+	"outro":      TypeOutro,
+	"аутро":      TypeOutro,
+	"концовка":   TypeOutro,
+	"hook":       TypeHook,
+}
+
+const labels = `(pre-chorus|prechorus|verse|chorus|refrain|bridge|intro|outro|hook|куплет|припев|бридж|интро|вступление|аутро|концовка)`
+
+// labelPattern matches label lines: anything in brackets starting with a label,
+// like "[Chorus]" or "(Куплет 1: Artist)", or a bare label like "Verse 2:"
+var labelPattern = regexp.MustCompile(`(?i)^(?:[\[(]\s*` + labels + `(?:[\s\d.:#-][^\])]*)?[\])]|` + labels + `(?:\s*\d+)?\s*:?)$`)
+
+// Stanza is a group of lines separated from the rest of the lyrics by blank lines
+type Stanza struct {
+	Type  string
+	Lines []string
+}
+
+func (s Stanza) Text() string {
+	return strings.Join(s.Lines, "\n")
+}
+
+// Split breaks lyrics into stanzas on blank lines. A label line such as "[Chorus]"
+// sets the type of the stanza it starts and is not kept as a line. Unlabeled stanzas
+// repeating a labeled one, like a chorus sung again, inherit its type.
+func Split(text string) []Stanza {
+	var stanzas []Stanza
+	current := Stanza{}
+
+	flush := func() {
+		if len(current.Lines) > 0 {
+			stanzas = append(stanzas, current)
+		}
+		current = Stanza{}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			if len(current.Lines) > 0 {
+				flush()
+			}
+		case labelType(line) != "":
+			flush()
+			current.Type = labelType(line)
+		default:
+			current.Lines = append(current.Lines, line)
+		}
+	}
+	flush()
+
+	known := make(map[string]string)
+	for i, s := range stanzas {
+		if s.Type != "" {
+			known[s.Text()] = s.Type
+		} else if t, ok := known[s.Text()]; ok {
+			stanzas[i].Type = t
+		}
+	}
+
+	return stanzas
+}
+
+func labelType(line string) string {
+	match := labelPattern.FindStringSubmatch(line)
+	if match == nil {
+		return ""
+	}
+	return labelTypes[strings.ToLower(match[1]+match[2])]
+}
@@ -0,0 +1,128 @@
+package lyrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Stanza
+	}{
+		{
+			name: "empty",
+			text: "",
+			want: nil,
+		},
+		{
+			name: "only blank lines",
+			text: "\n \n\t\n",
+			want: nil,
+		},
+		{
+			name: "single stanza",
+			text: "one\ntwo",
+			want: []Stanza{{Lines: []string{"one", "two"}}},
+		},
+		{
+			name: "backslash sequences are text",
+			text: `one\ntwo`,
+			want: []Stanza{{Lines: []string{`one\ntwo`}}},
+		},
+		{
+			name: "windows line breaks",
+			text: "one\r\ntwo\r\n\r\nthree",
+			want: []Stanza{
+				{Lines: []string{"one", "two"}},
+				{Lines: []string{"three"}},
+			},
+		},
+		{
+			name: "several blank lines and whitespace",
+			text: "  one  \n\n\n \n\ttwo\n\n",
+			want: []Stanza{
+				{Lines: []string{"one"}},
+				{Lines: []string{"two"}},
+			},
+		},
+		{
+			name: "bracketed labels",
+			text: "[Verse 1]\none\n\n[Chorus]\ntwo",
+			want: []Stanza{
+				{Type: TypeVerse, Lines: []string{"one"}},
+				{Type: TypeChorus, Lines: []string{"two"}},
+			},
+		},
+		{
+			name: "label with artist in parentheses",
+			text: "(Куплет 2: Artist)\nодин",
+			want: []Stanza{{Type: TypeVerse, Lines: []string{"один"}}},
+		},
+		{
+			name: "bare labels with colon and number",
+			text: "Pre-Chorus:\none\n\nBridge 2\ntwo\n\nприпев:\nтри",
+			want: []Stanza{
+				{Type: TypePreChorus, Lines: []string{"one"}},
+				{Type: TypeBridge, Lines: []string{"two"}},
+				{Type: TypeChorus, Lines: []string{"три"}},
+			},
+		},
+		{
+			name: "label inside a stanza starts a new one",
+			text: "one\n[Hook]\ntwo",
+			want: []Stanza{
+				{Lines: []string{"one"}},
+				{Type: TypeHook, Lines: []string{"two"}},
+			},
+		},
+		{
+			name: "label followed by a blank line",
+			text: "[Intro]\n\none\n\n[Outro]",
+			want: []Stanza{{Type: TypeIntro, Lines: []string{"one"}}},
+		},
+		{
+			name: "line mentioning a label is kept",
+			text: "the chorus goes on\nverse after verse",
+			want: []Stanza{{Lines: []string{"the chorus goes on", "verse after verse"}}},
+		},
+		{
+			name: "unknown bracket is kept",
+			text: "[Instrumental]\none",
+			want: []Stanza{{Lines: []string{"[Instrumental]", "one"}}},
+		},
+		{
+			name: "repeated stanza inherits the type",
+			text: "[Chorus]\nla la\n\nverse line\n\nla la",
+			want: []Stanza{
+				{Type: TypeChorus, Lines: []string{"la la"}},
+				{Lines: []string{"verse line"}},
+				{Type: TypeChorus, Lines: []string{"la la"}},
+			},
+		},
+		{
+			name: "only later labels are inherited",
+			text: "la la\n\n[Chorus]\nla la",
+			want: []Stanza{
+				{Lines: []string{"la la"}},
+				{Type: TypeChorus, Lines: []string{"la la"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Split(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q) = %#v, want %#v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStanzaText(t *testing.T) {
+	s := Stanza{Lines: []string{"one", "two"}}
+	if got := s.Text(); got != "one\ntwo" {
+		t.Errorf("Text() = %q, want %q", got, "one\ntwo")
+	}
+}
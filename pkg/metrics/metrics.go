@@ -0,0 +1,118 @@
+// Package metrics holds the Prometheus collectors of the service, they are served by Handler.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "musiclib"
+
+// Outcomes of a music API call
+const (
+	OutcomeSuccess        = "success"
+	OutcomeInvalidRequest = "invalid_request"
+	OutcomeNotFound       = "not_found"
+	OutcomeUnavailable    = "unavailable"
+	OutcomeBadResponse    = "bad_response"
+)
+
+// UnmatchedRoute labels requests no route matched, so that unknown paths do not create new series
+const UnmatchedRoute = "unmatched"
+
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by method, route template and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of repository methods, including every query and transaction they run.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"repository", "method"})
+
+	musicAPIRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "music_api",
+		Name:      "requests_total",
+		Help:      "Calls to the music API by outcome.",
+	}, []string{"outcome"})
+
+	musicAPIDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "music_api",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of calls to the music API by outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"outcome"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		queryDuration,
+		musicAPIRequests,
+		musicAPIDuration,
+	)
+}
+
+// Handler serves the registered metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDB exports the connection pool statistics of db: open and in use connections,
+// the number of waits for a connection and the time spent waiting
+func RegisterDB(db *sql.DB, name string) error {
+	return registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveRequest records a served HTTP request
+func ObserveRequest(method string, route string, status int, duration time.Duration) {
+	if route == "" {
+		route = UnmatchedRoute
+	}
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// ObserveQuery starts timing a repository method, the returned function records it:
+//
+//	defer metrics.ObserveQuery("song", "GetByID")()
+func ObserveQuery(repository string, method string) func() {
+	start := time.Now()
+	return func() {
+		queryDuration.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveMusicAPI records a call to the music API
+func ObserveMusicAPI(outcome string, duration time.Duration) {
+	musicAPIRequests.WithLabelValues(outcome).Inc()
+	musicAPIDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+}
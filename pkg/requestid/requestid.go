@@ -0,0 +1,51 @@
+// Package requestid carries the ID correlating the log lines and outbound calls of a request.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header is the header the ID is read from and echoed in, and passed on to other services
+const Header = "X-Request-ID"
+
+// maxLength bounds IDs taken from clients so they cannot bloat the logs
+const maxLength = 128
+
+type contextKey int
+
+const idKey contextKey = iota
+
+// New returns a random 128-bit ID
+func New() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// Valid reports whether an ID sent by a client is safe to reuse: short, printable ASCII without spaces
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// WithID returns a copy of ctx carrying the request ID
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey, id)
+}
+
+// FromContext returns the request ID stored in ctx or an empty string
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey).(string)
+	return id
+}
@@ -0,0 +1,54 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{"generated", New(), true},
+		{"uuid", "3f2504e0-4f89-11d3-9a0c-0305e82c3301", true},
+		{"punctuation", "req_1.2:3/4~", true},
+		{"longest", strings.Repeat("a", maxLength), true},
+		{"empty", "", false},
+		{"too long", strings.Repeat("a", maxLength+1), false},
+		{"space", "req 1", false},
+		{"tab", "req\t1", false},
+		{"newline injection", "req1\nlevel=error", false},
+		{"delete", "req\x7f", false},
+		{"non ascii", "запрос", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Valid(tt.id); got != tt.want {
+				t.Errorf("Valid(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	a, b := New(), New()
+	if len(a) != 32 {
+		t.Errorf("New() = %q, want 32 hex characters", a)
+	}
+	if a == b {
+		t.Errorf("New() returned %q twice", a)
+	}
+}
+
+func TestContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != "" {
+		t.Errorf("FromContext() without an ID = %q, want empty", got)
+	}
+	if got := FromContext(WithID(context.Background(), "req-1")); got != "req-1" {
+		t.Errorf("FromContext(WithID()) = %q, want %q", got, "req-1")
+	}
+}